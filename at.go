@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// findTestAt writes, as JSON, the innermost test enclosing the `file:line[:column]` location provided by the
+// user, or null if no test encloses it.
+func findTestAt(proc *args, writer io.Writer) error {
+	path, line, column, err := parseLocation(proc.dirs[0])
+	if err != nil {
+		return err
+	}
+
+	opts, err := proc.listOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	test, err := pkg.At(ctx, opts, path, line, column)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	marshal, err := json.Marshal(test)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errUnknown, err)
+	}
+
+	if proc.pretty {
+		return prettyPrint(marshal, writer)
+	}
+
+	_, err = fmt.Fprintf(writer, "%s\n", marshal)
+
+	return err
+}
+
+// parseLocation parses a `file:line[:column]` location, the path may contain colons.
+// It returns errLocation if the line or the column is not a positive number.
+func parseLocation(location string) (string, int, int, error) {
+	path, last, ok := cutLast(location)
+	if !ok {
+		return "", 0, 0, errLocation
+	}
+
+	number, err := strconv.Atoi(last)
+	if err != nil || number < 1 {
+		return "", 0, 0, errLocation
+	}
+
+	if rest, before, ok := cutLast(path); ok {
+		if line, err := strconv.Atoi(before); err == nil {
+			if line < 1 {
+				return "", 0, 0, errLocation
+			}
+
+			return rest, line, number, nil
+		}
+	}
+
+	return path, number, 0, nil
+}
+
+// cutLast slices the given value around its last colon.
+func cutLast(value string) (string, string, bool) {
+	i := strings.LastIndex(value, ":")
+	if i <= 0 {
+		return "", "", false
+	}
+
+	return value[:i], value[i+1:], true
+}
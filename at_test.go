@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseLocation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		location string
+		path     string
+		line     int
+		column   int
+		wantErr  bool
+	}{
+		{name: "line", location: "./pkg/sum_test.go:12", path: "./pkg/sum_test.go", line: 12},
+		{name: "line and column", location: "./pkg/sum_test.go:12:7", path: "./pkg/sum_test.go", line: 12, column: 7},
+		{name: "colon in the path", location: `C:\svc\sum_test.go:12`, path: `C:\svc\sum_test.go`, line: 12},
+		{name: "no line", location: "./pkg/sum_test.go", wantErr: true},
+		{name: "invalid line", location: "./pkg/sum_test.go:x", wantErr: true},
+		{name: "zero line", location: "./pkg/sum_test.go:0:1", wantErr: true},
+		{name: "zero column", location: "./pkg/sum_test.go:12:0", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, line, column, err := parseLocation(tt.location)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errLocation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.path, path)
+			require.Equal(t, tt.line, line)
+			require.Equal(t, tt.column, column)
+		})
+	}
+}
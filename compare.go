@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// compareTests writes, as JSON, the comparison of the tests discovered in the directories or packages provided by
+// the user with the tests run in the `go test -json` log, read from the input if no log is provided.
+func compareTests(proc *args, writer io.Writer) error {
+	result, err := listTests(proc)
+	if err != nil {
+		return err
+	}
+
+	input := proc.input
+
+	if proc.testLog != "" {
+		file, err := os.Open(proc.testLog)
+		if err != nil {
+			return wrapError(&pkg.WalkError{Path: proc.testLog, Err: err})
+		}
+
+		defer file.Close()
+
+		input = file
+	}
+
+	comparison, err := pkg.Compare(result.Tests, input)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errUnknown, err)
+	}
+
+	marshal, err := json.Marshal(comparison)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errUnknown, err)
+	}
+
+	if proc.pretty {
+		return prettyPrint(marshal, writer)
+	}
+
+	_, err = fmt.Fprintf(writer, "%s\n", marshal)
+
+	return err
+}
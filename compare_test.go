@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_compareTests(t *testing.T) {
+	t.Parallel()
+
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	events := `{"Action":"run","Package":"github.com/ninadingole/gotest-ls/tests","Test":"Test_subTestPattern"}
+{"Action":"run","Package":"github.com/ninadingole/gotest-ls/tests","Test":"Test_subTestPattern/subtest"}
+{"Action":"run","Package":"github.com/ninadingole/gotest-ls/tests","Test":"Test_subTestPattern/subtest_2#01"}
+{"Action":"run","Package":"github.com/ninadingole/gotest-ls/tests","Test":"Test_subTestPattern/dynamic"}
+`
+
+	logFile := filepath.Join(t.TempDir(), "test.json")
+	require.NoError(t, os.WriteFile(logFile, []byte(events), os.ModePerm))
+
+	for _, proc := range []*args{
+		{command: commandCompare, file: "./tests/subtest_test.go", input: strings.NewReader(events)},
+		{command: commandCompare, file: "./tests/subtest_test.go", testLog: logFile},
+	} {
+		var buffer bytes.Buffer
+		require.NoError(t, Process(proc, &buffer))
+
+		require.True(t, strings.HasSuffix(buffer.String(), "\n"))
+		require.JSONEq(t, strings.ReplaceAll(`{
+			"notRun": [],
+			"notDiscovered": [{
+				"name": "Test_subTestPattern/dynamic",
+				"package": "github.com/ninadingole/gotest-ls/tests",
+				"relativePath": "subtest_test.go",
+				"absolutePath": "##PATH##/tests/subtest_test.go",
+				"line": 10
+			}],
+			"renamed": [{
+				"name": "Test_subTestPattern/subtest_2#01",
+				"test": {
+					"name": "Test_subTestPattern/subtest_2",
+					"package": "github.com/ninadingole/gotest-ls/tests",
+					"module": "github.com/ninadingole/gotest-ls",
+					"moduleRoot": "##PATH##",
+					"fileName": "subtest_test.go",
+					"relativePath": "subtest_test.go",
+					"absolutePath": "##PATH##/tests/subtest_test.go",
+					"line": 15,
+					"pos": 193,
+					"runPattern": "^Test_subTestPattern$/^subtest_2$",
+					"command": ["go", "test", "-run", "^Test_subTestPattern$/^subtest_2$", "github.com/ninadingole/gotest-ls/tests"]
+				}
+			}]
+		}`, "##PATH##", pwd), buffer.String())
+	}
+}
@@ -2,19 +2,151 @@
 //
 // Usage:
 //
-//	gotest-ls [flags] [directories...]
+//	gotest-ls [flags] [directories|packages...]
+//	gotest-ls watch [flags] [directories|packages...]
+//	gotest-ls pattern [test names...]
+//	gotest-ls shard --total N --index i [flags] [directories|packages...]
+//	gotest-ls ingest --timings path [flags] [go test -json files...]
+//	gotest-ls compare [--log path] [flags] [directories|packages...]
+//	gotest-ls verify [flags] [directories|packages...]
+//	gotest-ls run [flags] [directories|packages...]
+//	gotest-ls at [flags] file:line[:column]
+//
+// Directories are walked recursively, skipping the `vendor`, `testdata`, `_` and `.` prefixed directories
+// ignored by the go tool. Package patterns like `./...` or `github.com/acme/svc/...` are resolved with the
+// same semantics as `go test` using the local module, the network is never used.
 //
 // Examples:
 //
 //	gotest-ls .
+//	gotest-ls ./...
+//	gotest-ls github.com/acme/svc/...
 //	gotest-ls -p ./cmd
 //	gotest-ls -p ./cmd ./pkg
 //	gotest-ls -f ./pkg/random_test.go
 //	gotest-ls -p -f ./pkg/random_test.go
+//	gotest-ls -tags slow,owner=payments ./pkg
+//	gotest-ls -w
+//	gotest-ls --exclude '**/mocks/**' --skip-generated ./...
+//	gotest-ls --run 'Sum/^negative' --skip Slow --kind test ./...
+//	gotest-ls --changed-since origin/main --format text ./...
+//	gotest-ls watch --interval 2s ./...
+//	gotest-ls --format text ./pkg | grep Sum | gotest-ls pattern
+//	gotest-ls shard --total 12 --index 3 --timings timings.json ./...
+//	go test -json ./... | gotest-ls ingest --timings timings.json
+//	gotest-ls compare --log test.json -p ./...
+//	gotest-ls verify ./...
+//	gotest-ls run -tags slow ./...
+//	gotest-ls at ./pkg/random_test.go:42:7
 //
 // Flags:
 //
 //	-f, --file string   Path to a file, cannot be used with directories
 //	-h, --help          help for gotest-ls
+//	-j int              Number of files parsed concurrently, defaults to GOMAXPROCS
 //	-p, --pretty        Pretty print the output in JSON format
+//	--format string     Output format: json, ndjson (one test per line), text (one test name per line), table,
+//	                    template or junit (JUnit XML test plan), defaults to json
+//	--template string   Go template executed for each test by the template format
+//	--template-file path File containing the Go template executed for each test by the template format
+//	-w, --workspace     List the tests of all the modules in the go.work workspace
+//	--tags string       Comma separated tags, only tests annotated with all of them are listed
+//	--run regexp        Only list the tests selected by the go test -run pattern, matched level by level
+//	--skip regexp       Skip the tests matching the go test -skip pattern
+//	--kind string       Comma separated kinds of the tests to list: test, benchmark, example or fuzz
+//	--max-depth int     Only list the tests nested in at most the given levels, 1 for the top level tests
+//	--include glob      Only list the test files matching the glob, can be repeated
+//	--exclude glob      Skip the test files matching the glob, can be repeated
+//	--no-ignore         Do not honour the .gitignore and .ignore files
+//	--skip-generated    Skip the generated test files
+//	--tolerant          Report syntax errors as diagnostics and list the tests of the parseable parts
+//	--strict            Exit with an error if diagnostics are reported in tolerant mode
+//	--build-tags string Comma separated build tags, only files matching their build constraints are listed
+//	--base string       Directory the relative paths are computed from
+//	--timeout duration  Stop listing the tests after the given duration
+//	--stream            Write the tests of each file as soon as it is parsed, one JSON object per line
+//	--cache             Cache the result of listing each file in gotest-ls in the user cache directory
+//	--no-cache          Parse all the files without reading or writing the cache, even with --cache
+//	--cache-dir string  Directory of the cache, enables the cache in the given directory
+//	--interval duration Interval between two polls of the watch command, defaults to 1s
+//	--total int         Number of shards of the shard command
+//	--index int         Zero-based index of the shard printed by the shard command
+//	--timings path      Timing file with the average durations of the tests, attached as avgDuration, weighting
+//	                    the shards and updated by the ingest command
+//	--log path          go test -json log of the compare command, defaults to the standard input
+//	--changed-since ref Only list the tests changed since the git ref, including the uncommitted changes
+//
+// The watch command polls the directories or packages every --interval and writes each change of the tests as
+// a JSON object on its own line, with the `type` of the change (`added`, `removed`, `moved` or `changed`), the
+// `test` and its `previous` details. The directories and packages are only resolved again, running `go list`,
+// when the directory of a test file or one of its parents in the module changes, and at least every minute.
+//
+// Each test contains the escaped pattern anchoring every level of its name, `runPattern` for tests and examples,
+// `benchPattern` for benchmarks and `fuzzPattern` for fuzz targets, and the `command` running it with `go test`.
+// The pattern command combines the test names provided as arguments, or read one per line from the standard
+// input, into the minimal pattern matching all of them.
+//
+// The shard command partitions the top level tests into --total balanced shards and prints the `go test -run`
+// command of each package for the shard at --index. Subtests always run with their top level test. The tests
+// are weighted by their average duration in the --timings file, or all weigh the same without it, and are
+// always assigned to the same shard.
+//
+// The ingest command reads the `go test -json` files provided as arguments, or the standard input, and records
+// the durations of the tests listed in the current module, or the workspace with -w, in the --timings file.
+// Each test keeps the rolling average of its last 10 durations, attached as `avgDuration` to the listing.
+//
+// The compare command compares the discovered tests with the tests run in the `go test -json` --log, and writes
+// as JSON the tests which did not run (`notRun`), the tests which ran but were not discovered (`notDiscovered`)
+// and the subtests which ran under another name (`renamed`), along with their file and line.
+//
+// The verify command runs `go test -list '.*'` in each package and writes as JSON the top level tests listed by
+// `go test` which were not found (`missing`) and the tests found which are not listed (`extra`). It exits with
+// the code 7 if the tests of a package don't match. The tests skipped by --include, --exclude or --skip-generated
+// are reported as missing, --run, --skip and --kind select the tests listed by `go test` too. The fuzz targets
+// are verified as `go test` lists them.
+//
+// The run command runs the tests found with `go test -json`, once per package with the minimal `-run` and
+// `-bench` patterns, and writes the listing as JSON with the `status` (`pass`, `fail`, `skip` or `notrun`),
+// the `duration` and the `output` of each test. It exits with the code 8 if a test failed.
+//
+// With --cache or --cache-dir, the result of listing each file is cached, the files which did not change since
+// the previous listing are not parsed again. The cache entries unused for a week are pruned.
+//
+// The include and exclude globs use the doublestar syntax and are matched against the relative path of the
+// test file. The paths ignored by the `.gitignore` and `.ignore` files found while walking the directories are
+// skipped unless --no-ignore is provided. Generated files are detected by the standard
+// `// Code generated ... DO NOT EDIT.` header.
+//
+// With --format ndjson each test is written as a JSON object on its own line as soon as its file is parsed.
+// With --format text the name of each test is written on its own line, with --format table the package, the
+// test, its kind and its file:line are written in aligned columns, coloured when the output is a terminal unless
+// NO_COLOR is set to a non-empty value. The compare, verify, run and at commands only write JSON and reject any
+// other format.
+//
+// With --format template the Go template provided by --template or --template-file is executed for each test,
+// the quoteRegex, rel and join helper functions are available.
+//
+// With --format junit the tests are written as a JUnit XML test plan, with a test suite per package and a test
+// case per test marked as skipped as it is not run yet. Without tests an empty test plan is written.
+//
+// With --stream the tests of each file are written as soon as the file is parsed, each line is a JSON object
+// with the `tests` and the `diagnostics` of a file.
+//
+// Each test reports the path and the root directory of the module it belongs to. With the workspace flag the
+// tests of every module used by the `go.work` file are listed and their relative paths are computed from the
+// root of their module.
+//
+// By default a syntax error in a test file fails the listing. In tolerant mode the output is an object with the
+// `tests` found in the parseable parts of the files and the `diagnostics` (file, line, column and message) of
+// the syntax errors.
+//
+// Errors are written to stderr. Exit codes: 1 unknown error, 2 invalid arguments or glob patterns, 3 the test
+// files can't be found or read, 4 a test file or the go.work file can't be parsed or diagnostics are reported
+// with --strict, 5 the path of a test file can't be resolved, 6 the listing was interrupted or --timeout expired.
+//
+// Tests can be annotated with tags using a `gotest-ls:tags` comment, tags in the form `key=value`
+// are reported as labels. Subtests inherit the tags of their parent test.
+//
+//	// gotest-ls:tags slow,db owner=payments
+//	func TestPayment(t *testing.T) {}
 package main
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// Exit codes of the program, each category of error has its own exit code.
+const (
+	exitCodeUnknown = 1
+	exitCodeUsage   = 2
+	exitCodeWalk    = 3
+	exitCodeParse   = 4
+	exitCodePath    = 5
+	exitCodeTimeout = 6
+	exitCodeVerify  = 7
+	exitCodeFailed  = 8
+)
+
+var (
+	// errWalk is the error message when the test files can't be found or read.
+	errWalk = errors.New("ERROR: cannot read the test files")
+
+	// errParse is the error message when a test file or a go.work file can't be parsed.
+	errParse = errors.New("ERROR: cannot parse the test files")
+
+	// errPath is the error message when the path of a test file can't be resolved.
+	errPath = errors.New("ERROR: cannot resolve the path of the test files")
+
+	// errPattern is the error message when an include or exclude glob pattern is invalid.
+	errPattern = errors.New("ERROR: invalid glob pattern")
+
+	// errMatch is the error message when a run or skip pattern is invalid.
+	errMatch = errors.New("ERROR: invalid run or skip pattern")
+
+	// errGit is the error message when the changes of the git repository can't be read.
+	errGit = errors.New("ERROR: cannot read the git changes")
+
+	// errVerify is the error message when the tests found don't match the tests listed by `go test -list`.
+	errVerify = errors.New("ERROR: the tests found do not match go test -list")
+
+	// errFailed is the error message when tests run by the run command failed.
+	errFailed = errors.New("ERROR: tests failed")
+
+	// errCancelled is the error message when the listing is interrupted or times out.
+	errCancelled = errors.New("ERROR: listing the tests was cancelled")
+)
+
+// usageErrors are the errors caused by invalid arguments.
+var usageErrors = []error{
+	errPathIssue, errNotAFile, errWorkspaceArgs, errNoWorkspace, errFormat, errStreamFormat, errTemplateArgs, errTemplate,
+	errShardArgs, errTimingsArgs, errLocation, errKind, errCommandFormat,
+}
+
+// wrapError wraps the error returned by the pkg package with the message of its category.
+func wrapError(err error) error {
+	var (
+		walkErr    *pkg.WalkError
+		parseErr   *pkg.ParseError
+		pathErr    *pkg.PathError
+		patternErr *pkg.PatternError
+		matchErr   *pkg.MatchError
+		gitErr     *pkg.GitError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%s: %w", errCancelled, err)
+	case errors.As(err, &parseErr):
+		return fmt.Errorf("%s: %w", errParse, err)
+	case errors.As(err, &walkErr):
+		return fmt.Errorf("%s: %w", errWalk, err)
+	case errors.As(err, &pathErr):
+		return fmt.Errorf("%s: %w", errPath, err)
+	case errors.As(err, &patternErr):
+		return fmt.Errorf("%s: %w", errPattern, err)
+	case errors.As(err, &matchErr):
+		return fmt.Errorf("%s: %w", errMatch, err)
+	case errors.As(err, &gitErr):
+		return fmt.Errorf("%s: %w", errGit, err)
+	default:
+		return fmt.Errorf("%s: %w", errUnknown, err)
+	}
+}
+
+// exitCode returns the exit code of the program for the given error.
+func exitCode(err error) int {
+	var (
+		walkErr    *pkg.WalkError
+		parseErr   *pkg.ParseError
+		pathErr    *pkg.PathError
+		patternErr *pkg.PatternError
+		matchErr   *pkg.MatchError
+		gitErr     *pkg.GitError
+	)
+
+	for _, usageErr := range usageErrors {
+		if errors.Is(err, usageErr) {
+			return exitCodeUsage
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return exitCodeTimeout
+	case errors.As(err, &patternErr), errors.As(err, &matchErr):
+		return exitCodeUsage
+	case errors.Is(err, errVerify):
+		return exitCodeVerify
+	case errors.Is(err, errFailed):
+		return exitCodeFailed
+	case errors.As(err, &parseErr), errors.Is(err, errDiagnostics):
+		return exitCodeParse
+	case errors.As(err, &walkErr), errors.As(err, &gitErr):
+		return exitCodeWalk
+	case errors.As(err, &pathErr):
+		return exitCodePath
+	default:
+		return exitCodeUnknown
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_exitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "usage error", err: errPathIssue, want: exitCodeUsage},
+		{name: "invalid glob pattern", err: wrapError(&pkg.PatternError{Pattern: "[", Err: errors.New("bad")}), want: exitCodeUsage},
+		{name: "invalid run pattern", err: wrapError(&pkg.MatchError{Pattern: "(", Err: errors.New("bad")}), want: exitCodeUsage},
+		{name: "walk error", err: wrapError(&pkg.WalkError{Path: "./x", Err: errors.New("lstat")}), want: exitCodeWalk},
+		{name: "git error", err: wrapError(&pkg.GitError{Dir: ".", Ref: "main", Err: errors.New("exit status 128")}), want: exitCodeWalk},
+		{name: "parse error", err: wrapError(&pkg.ParseError{File: "x_test.go", Err: errors.New("syntax")}), want: exitCodeParse},
+		{name: "diagnostics in strict mode", err: fmt.Errorf("%w: 1 syntax errors", errDiagnostics), want: exitCodeParse},
+		{name: "path error", err: wrapError(&pkg.PathError{Path: "x_test.go", Err: errors.New("abs")}), want: exitCodePath},
+		{name: "timeout", err: wrapError(context.DeadlineExceeded), want: exitCodeTimeout},
+		{name: "interrupted", err: wrapError(context.Canceled), want: exitCodeTimeout},
+		{name: "verify mismatch", err: fmt.Errorf("%w: 1 packages", errVerify), want: exitCodeVerify},
+		{name: "failed tests", err: fmt.Errorf("%w: 1 tests", errFailed), want: exitCodeFailed},
+		{name: "unknown error", err: wrapError(errors.New("boom")), want: exitCodeUnknown},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+func Test_wrapError(t *testing.T) {
+	t.Parallel()
+
+	err := wrapError(&pkg.ParseError{File: "x_test.go", Line: 3, Column: 1, Err: errors.New("expected declaration")})
+	require.Equal(t, "ERROR: cannot parse the test files: parse x_test.go: expected declaration", err.Error())
+
+	var parseErr *pkg.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 3, parseErr.Line)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// Output formats supported by the format flag.
+const (
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatText     = "text"
+	formatTable    = "table"
+	formatTemplate = "template"
+	formatJUnit    = "junit"
+)
+
+// formats are the output formats supported by the format flag.
+var formats = []string{formatJSON, formatNDJSON, formatText, formatTable, formatTemplate, formatJUnit}
+
+// templateFuncs are the helper functions available in the templates of the template format.
+var templateFuncs = template.FuncMap{
+	// quoteRegex escapes the regular expression metacharacters of the value, like regexp.QuoteMeta.
+	"quoteRegex": regexp.QuoteMeta,
+	// rel returns the path relative to the base directory, or the path itself if it can't be made relative.
+	"rel": relativePath,
+	// join concatenates the values with the separator, like strings.Join.
+	"join": strings.Join,
+}
+
+// ANSI escape codes used to colour the table output.
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorFaint  = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+)
+
+// kindColors are the colours of the kinds of the tests in the table output.
+var kindColors = map[pkg.Kind]string{
+	pkg.KindTest:      colorGreen,
+	pkg.KindBenchmark: colorYellow,
+	pkg.KindFuzz:      colorRed,
+	pkg.KindExample:   colorBlue,
+}
+
+// isValidFormat checks if the given format is supported, an empty format is the default JSON format.
+func isValidFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// useColor checks if the output written to the given file should be coloured, the output is coloured when the
+// file is a terminal and the NO_COLOR environment variable is empty or not set.
+// See https://no-color.org for more details.
+func useColor(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	stat, err := file.Stat()
+
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// writeText writes the name of each test on its own line.
+func writeText(writer io.Writer, tests []pkg.TestDetail) error {
+	for _, test := range tests {
+		if _, err := fmt.Fprintln(writer, test.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTable writes the tests as a table with aligned package, test, kind and file:line columns.
+// The header and the kinds are coloured if color is true.
+func writeTable(writer io.Writer, tests []pkg.TestDetail, color bool) error {
+	rows := [][]string{{"PACKAGE", "TEST", "KIND", "FILE:LINE"}}
+
+	for _, test := range tests {
+		rows = append(rows, []string{
+			test.Package,
+			test.Name,
+			string(test.Kind()),
+			test.RelativePath + ":" + strconv.Itoa(test.Line),
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	for r, row := range rows {
+		var line strings.Builder
+
+		for i, cell := range row {
+			padding := ""
+			if i < len(row)-1 {
+				padding = strings.Repeat(" ", widths[i]-len(cell)+2)
+			}
+
+			line.WriteString(colorize(cell, cellColor(r, i, tests), color) + padding)
+		}
+
+		if _, err := fmt.Fprintln(writer, line.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cellColor returns the colour of the cell of the table at the given row and column, the first row is the header.
+func cellColor(row, column int, tests []pkg.TestDetail) string {
+	switch {
+	case row == 0:
+		return colorBold
+	case column == 0:
+		return colorCyan
+	case column == 2:
+		return kindColors[tests[row-1].Kind()]
+	case column == 3:
+		return colorFaint
+	default:
+		return ""
+	}
+}
+
+// colorize wraps the value with the given ANSI colour if enabled.
+func colorize(value, color string, enabled bool) string {
+	if !enabled || color == "" || value == "" {
+		return value
+	}
+
+	return color + value + colorReset
+}
+
+// parseTemplate parses the template of the template format, provided inline or in a file.
+func parseTemplate(text, file string) (*template.Template, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errTemplate, err)
+		}
+
+		text = string(data)
+	}
+
+	if text == "" {
+		return nil, errTemplateArgs
+	}
+
+	tmpl, err := template.New("gotest-ls").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errTemplate, err)
+	}
+
+	return tmpl, nil
+}
+
+// writeTemplate executes the template for each test, each execution is followed by a new line like `go list -f`.
+func writeTemplate(writer io.Writer, tmpl *template.Template, tests []pkg.TestDetail) error {
+	for _, test := range tests {
+		if err := tmpl.Execute(writer, test); err != nil {
+			return fmt.Errorf("%w: %v", errTemplate, err)
+		}
+
+		if _, err := fmt.Fprintln(writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relativePath returns the path relative to the base directory, both are made absolute first so a path can be
+// made relative to the current directory with `rel "." .AbsolutePath`. The path is returned unchanged if it can't
+// be made relative.
+func relativePath(base, path string) string {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return path
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+// writeDiagnostics writes each diagnostic on its own line in the `file:line:column: message` format.
+func writeDiagnostics(writer io.Writer, diagnostics []pkg.Diagnostic) {
+	for _, d := range diagnostics {
+		_, _ = fmt.Fprintf(writer, "%s:%d:%d: %s\n", d.File, d.Line, d.Column, d.Message)
+	}
+}
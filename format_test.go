@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []pkg.TestDetail{
+		{Name: "BenchmarkSum", Package: "github.com/acme/svc", RelativePath: "svc/sum_test.go", Line: 12},
+		{Name: "TestSum/negative_numbers", Package: "github.com/acme/svc", RelativePath: "svc/sum_test.go", Line: 7},
+	}
+
+	t.Run("without colour", func(t *testing.T) {
+		t.Parallel()
+
+		writer := &bytes.Buffer{}
+		require.NoError(t, writeTable(writer, tests, false))
+		require.Equal(t, `PACKAGE              TEST                      KIND       FILE:LINE
+github.com/acme/svc  BenchmarkSum              benchmark  svc/sum_test.go:12
+github.com/acme/svc  TestSum/negative_numbers  test       svc/sum_test.go:7
+`, writer.String())
+	})
+
+	t.Run("with colour", func(t *testing.T) {
+		t.Parallel()
+
+		writer := &bytes.Buffer{}
+		require.NoError(t, writeTable(writer, tests[:1], true))
+		require.Equal(t, "\x1b[1mPACKAGE\x1b[0m              \x1b[1mTEST\x1b[0m          \x1b[1mKIND\x1b[0m       "+
+			"\x1b[1mFILE:LINE\x1b[0m\n"+
+			"\x1b[36mgithub.com/acme/svc\x1b[0m  BenchmarkSum  \x1b[33mbenchmark\x1b[0m  \x1b[2msvc/sum_test.go:12\x1b[0m\n",
+			writer.String())
+	})
+}
+
+func Test_writeText(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+	require.NoError(t, writeText(writer, []pkg.TestDetail{{Name: "TestSum"}, {Name: "TestSum/negative_numbers"}}))
+	require.Equal(t, "TestSum\nTestSum/negative_numbers\n", writer.String())
+}
+
+func Test_useColor(t *testing.T) {
+	regular, err := os.Create(filepath.Join(t.TempDir(), "output.txt"))
+	require.NoError(t, err)
+	t.Cleanup(func() { regular.Close() })
+
+	// the null device is a character device, like a terminal.
+	device, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { device.Close() })
+
+	tests := []struct {
+		name    string
+		noColor string
+		file    *os.File
+		want    bool
+	}{
+		{name: "terminal with an empty NO_COLOR", file: device, want: true},
+		{name: "terminal with NO_COLOR", noColor: "1", file: device},
+		{name: "regular file", file: regular},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			require.Equal(t, tt.want, useColor(tt.file))
+		})
+	}
+}
+
+func Test_writeTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []pkg.TestDetail{
+		{
+			Name:         "TestSum/1+1",
+			Package:      "github.com/acme/svc",
+			AbsolutePath: "/src/svc/sum_test.go",
+			Line:         7,
+			Tags:         []string{"fast", "math"},
+		},
+	}
+
+	tmpl, err := parseTemplate(`{{.Package}} {{quoteRegex .Name}} {{rel "/src" .AbsolutePath}}:{{.Line}} {{join .Tags ","}} {{.Kind}}`, "")
+	require.NoError(t, err)
+
+	writer := &bytes.Buffer{}
+	require.NoError(t, writeTemplate(writer, tmpl, tests))
+	require.Equal(t, "github.com/acme/svc TestSum/1\\+1 svc/sum_test.go:7 fast,math test\n", writer.String())
+
+	_, err = parseTemplate("", "")
+	require.ErrorIs(t, err, errTemplateArgs)
+
+	_, err = parseTemplate("{{.Name", "")
+	require.ErrorIs(t, err, errTemplate)
+
+	tmpl, err = parseTemplate("{{.Missing}}", "")
+	require.NoError(t, err)
+	require.ErrorIs(t, writeTemplate(writer, tmpl, tests), errTemplate)
+}
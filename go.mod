@@ -2,7 +2,11 @@ module github.com/ninadingole/gotest-ls
 
 go 1.19
 
-require github.com/stretchr/testify v1.8.2
+require (
+	github.com/bmatcuk/doublestar/v4 v4.6.1
+	github.com/stretchr/testify v1.8.2
+	golang.org/x/mod v0.14.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
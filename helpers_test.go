@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// goModFile is the go.mod file of the modules written by the tests.
+const goModFile = "module example.com/app\n\ngo 1.19\n"
+
+// writeFiles writes the given files, by path relative to the given directory, creating their directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), os.ModePerm))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// ingestTimings records in the timing file the durations of the tests found in the `go test -json` files
+// provided as arguments, or read from the input without arguments. Only the durations of the tests listed in
+// the current module, or the workspace if requested, are recorded.
+func ingestTimings(proc *args, writer io.Writer) error {
+	if proc.timings == "" {
+		return errTimingsArgs
+	}
+
+	timings, err := pkg.ReadTimings(proc.timings)
+	if errors.Is(err, fs.ErrNotExist) {
+		timings, err = make(pkg.Timings), nil
+	}
+
+	if err != nil {
+		return wrapError(err)
+	}
+
+	listing := *proc
+	listing.dirs, listing.file = nil, ""
+
+	if !listing.workspace {
+		listing.dirs = []string{"./..."}
+	}
+
+	result, err := listTests(&listing)
+	if err != nil {
+		return err
+	}
+
+	var recorded int
+
+	ingest := func(r io.Reader) error {
+		n, err := timings.Ingest(r, result.Tests)
+		recorded += n
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", errUnknown, err)
+		}
+
+		return nil
+	}
+
+	if len(proc.dirs) == 0 {
+		if err := ingest(proc.input); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range proc.dirs {
+		file, err := os.Open(path)
+		if err != nil {
+			return wrapError(&pkg.WalkError{Path: path, Err: err})
+		}
+
+		err = ingest(file)
+		_ = file.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := pkg.WriteTimings(proc.timings, timings); err != nil {
+		return wrapError(err)
+	}
+
+	_, err = fmt.Fprintf(writer, "Recorded %d durations in %s\n", recorded, proc.timings)
+
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ingestTimings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path   = filepath.Join(t.TempDir(), "timings.json")
+		events = `{"Action":"pass","Package":"github.com/ninadingole/gotest-ls/tests","Test":"TestSomething","Elapsed":1.5}
+{"Action":"pass","Package":"github.com/ninadingole/gotest-ls/tests","Test":"TestUnknown","Elapsed":2}
+`
+	)
+
+	var buffer bytes.Buffer
+	require.NoError(t, Process(&args{command: commandIngest, timings: path, input: strings.NewReader(events)}, &buffer))
+	require.Equal(t, "Recorded 1 durations in "+path+"\n", buffer.String())
+
+	// the durations are averaged with the durations already recorded.
+	eventsFile := filepath.Join(t.TempDir(), "events.json")
+	require.NoError(t, os.WriteFile(eventsFile, []byte(strings.ReplaceAll(events, "1.5", "2.5")), os.ModePerm))
+
+	buffer.Reset()
+	require.NoError(t, Process(&args{command: commandIngest, timings: path, dirs: []string{eventsFile}}, &buffer))
+	require.Equal(t, "Recorded 1 durations in "+path+"\n", buffer.String())
+
+	buffer.Reset()
+	require.NoError(t, Process(&args{file: "./tests/sample_test.go", timings: path}, &buffer))
+	require.Contains(t, buffer.String(), `"avgDuration":2`)
+}
+
+func Test_ingestTimingsRequiresTimingFile(t *testing.T) {
+	t.Parallel()
+
+	err := Process(&args{command: commandIngest, input: strings.NewReader("")}, &bytes.Buffer{})
+	require.ErrorIs(t, err, errTimingsArgs)
+	require.Equal(t, exitCodeUsage, exitCode(err))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// junitNotRun is the message of the skipped element of the test cases, the tests are listed but not run.
+const junitNotRun = "not yet run"
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite contains the test cases of a package.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a test or a subtest.
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr"`
+	Line      int          `xml:"line,attr"`
+	Skipped   junitSkipped `xml:"skipped"`
+}
+
+// junitSkipped marks a test case as skipped with the given message.
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit writes the tests as a JUnit XML test plan with a test suite per package and a test case per test,
+// all the test cases are marked as skipped as they are not run yet. The class name of a test case is the import
+// path of its package, or the directory of its file if it is not part of a module.
+func writeJUnit(writer io.Writer, tests []pkg.TestDetail) error {
+	var (
+		report = junitTestSuites{Tests: len(tests), Skipped: len(tests)}
+		suites = make(map[string]*junitTestSuite)
+		names  []string
+	)
+
+	for _, test := range tests {
+		className := test.Package
+		if className == "" {
+			className = filepath.ToSlash(filepath.Dir(test.RelativePath))
+		}
+
+		suite, ok := suites[className]
+		if !ok {
+			suite = &junitTestSuite{Name: className}
+			suites[className] = suite
+			names = append(names, className)
+		}
+
+		suite.Tests++
+		suite.Skipped++
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      test.Name,
+			ClassName: className,
+			File:      filepath.ToSlash(test.RelativePath),
+			Line:      test.Line,
+			Skipped:   junitSkipped{Message: junitNotRun},
+		})
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		report.Suites = append(report.Suites, *suites[name])
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "\t")
+
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n")
+
+	return err
+}
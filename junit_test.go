@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeJUnit(t *testing.T) {
+	t.Parallel()
+
+	tests := []pkg.TestDetail{
+		{Name: "TestSum", Package: "github.com/acme/svc/math", RelativePath: "math/sum_test.go", Line: 7},
+		{Name: "TestHandler/<nil>_body", Package: "github.com/acme/svc/api", RelativePath: "api/api_test.go", Line: 12},
+		{Name: "TestScript", RelativePath: "scripts/script_test.go", Line: 3},
+		{Name: "TestSum/negative", Package: "github.com/acme/svc/math", RelativePath: "math/sum_test.go", Line: 9},
+	}
+
+	writer := &bytes.Buffer{}
+	require.NoError(t, writeJUnit(writer, tests))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" skipped="4" failures="0" errors="0">
+	<testsuite name="github.com/acme/svc/api" tests="1" skipped="1" failures="0" errors="0">
+		<testcase name="TestHandler/&lt;nil&gt;_body" classname="github.com/acme/svc/api" file="api/api_test.go" line="12">
+			<skipped message="not yet run"></skipped>
+		</testcase>
+	</testsuite>
+	<testsuite name="github.com/acme/svc/math" tests="2" skipped="2" failures="0" errors="0">
+		<testcase name="TestSum" classname="github.com/acme/svc/math" file="math/sum_test.go" line="7">
+			<skipped message="not yet run"></skipped>
+		</testcase>
+		<testcase name="TestSum/negative" classname="github.com/acme/svc/math" file="math/sum_test.go" line="9">
+			<skipped message="not yet run"></skipped>
+		</testcase>
+	</testsuite>
+	<testsuite name="scripts" tests="1" skipped="1" failures="0" errors="0">
+		<testcase name="TestScript" classname="scripts" file="scripts/script_test.go" line="3">
+			<skipped message="not yet run"></skipped>
+		</testcase>
+	</testsuite>
+</testsuites>
+`, writer.String())
+}
+
+func Test_writeJUnitWithoutTests(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+	require.NoError(t, writeJUnit(writer, nil))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="0" skipped="0" failures="0" errors="0"></testsuites>
+`, writer.String())
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(writer.Bytes(), &report))
+	require.Empty(t, report.Suites)
+}
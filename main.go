@@ -1,13 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/ninadingole/gotest-ls/pkg"
 )
@@ -21,6 +28,126 @@ var (
 
 	// help is a flag to print the help text.
 	help = flag.Bool("h", false, "help")
+
+	// workspace is a flag to list the tests of all the modules in the go workspace.
+	workspace = flag.Bool("w", false, "workspace")
+
+	// tags is a flag to filter the tests by the tags provided in the `gotest-ls:tags` annotations.
+	tags = flag.String("tags", "", "comma separated list of tags")
+
+	// runPattern is a flag to only list the tests selected by a `go test -run` pattern.
+	runPattern = flag.String("run", "", "go test -run pattern")
+
+	// skipPattern is a flag to skip the tests matching a `go test -skip` pattern.
+	skipPattern = flag.String("skip", "", "go test -skip pattern")
+
+	// kinds is a flag to only list the tests of the given kinds.
+	kinds = flag.String("kind", "", "comma separated list of kinds")
+
+	// maxDepth is a flag to only list the tests nested in at most the given number of levels.
+	maxDepth = flag.Int("max-depth", 0, "maximum depth")
+
+	// noIgnore is a flag to list the tests in the paths ignored by the `.gitignore` and `.ignore` files.
+	noIgnore = flag.Bool("no-ignore", false, "do not honour .gitignore and .ignore files")
+
+	// skipGenerated is a flag to skip the generated test files.
+	skipGenerated = flag.Bool("skip-generated", false, "skip generated files")
+
+	// tolerant is a flag to report the syntax errors as diagnostics instead of failing.
+	tolerant = flag.Bool("tolerant", false, "report syntax errors as diagnostics")
+
+	// strict is a flag to exit with an error if diagnostics are reported in tolerant mode.
+	strict = flag.Bool("strict", false, "fail on diagnostics")
+
+	// workers is a flag to set the number of files parsed concurrently.
+	workers = flag.Int("j", 0, "number of workers")
+
+	// buildTags is a flag to only list the files matching the build constraints with the given build tags.
+	buildTags = flag.String("build-tags", "", "comma separated list of build tags")
+
+	// baseDir is a flag to set the directory the relative paths are computed from.
+	baseDir = flag.String("base", "", "base directory of the relative paths")
+
+	// timeout is a flag to stop listing the tests after the given duration.
+	timeout = flag.Duration("timeout", 0, "timeout")
+
+	// stream is a flag to write the tests of each file as soon as the file is parsed.
+	stream = flag.Bool("stream", false, "stream the tests")
+
+	// useCache is a flag to cache the result of listing each file in the default cache directory.
+	useCache = flag.Bool("cache", false, "enable the cache")
+
+	// noCache is a flag to parse all the files without using the cache.
+	noCache = flag.Bool("no-cache", false, "disable the cache")
+
+	// cacheDir is a flag to set the directory of the cache.
+	cacheDir = flag.String("cache-dir", "", "cache directory")
+
+	// format is a flag to set the output format.
+	format = flag.String("format", formatJSON, "output format")
+
+	// templateText is a flag with the template executed for each test by the template format.
+	templateText = flag.String("template", "", "template")
+
+	// templateFile is a flag with the path of the file containing the template of the template format.
+	templateFile = flag.String("template-file", "", "template file")
+
+	// interval is a flag to set the interval between two polls of the watch command.
+	interval = flag.Duration("interval", time.Second, "watch interval")
+
+	// total is a flag with the number of shards of the shard command.
+	total = flag.Int("total", 0, "number of shards")
+
+	// index is a flag with the zero-based index of the shard printed by the shard command.
+	index = flag.Int("index", 0, "shard index")
+
+	// timings is a flag with the path of the timing file with the durations of the tests.
+	timings = flag.String("timings", "", "timing file")
+
+	// testLog is a flag with the path of the `go test -json` log of the compare command.
+	testLog = flag.String("log", "", "go test -json log")
+
+	// changedSince is a flag with the git ref the tests are listed as changed since.
+	changedSince = flag.String("changed-since", "", "git ref")
+)
+
+const (
+	// commandWatch is the command which watches the tests and reports how they change.
+	commandWatch = "watch"
+
+	// commandPattern is the command which combines the names of a selection of tests into the minimal pattern.
+	commandPattern = "pattern"
+
+	// commandShard is the command which prints the go test commands running a shard of the tests.
+	commandShard = "shard"
+
+	// commandIngest is the command which records the durations of the tests run by `go test -json`.
+	commandIngest = "ingest"
+
+	// commandCompare is the command which compares the discovered tests with the tests run by `go test -json`.
+	commandCompare = "compare"
+
+	// commandVerify is the command which compares the tests found with the tests listed by `go test -list`.
+	commandVerify = "verify"
+
+	// commandRun is the command which runs the tests found and reports their results.
+	commandRun = "run"
+
+	// commandAt is the command which finds the innermost test enclosing a location of a test file.
+	commandAt = "at"
+)
+
+// commands are the commands which can be provided as the first argument.
+var commands = []string{
+	commandWatch, commandPattern, commandShard, commandIngest, commandCompare, commandVerify, commandRun, commandAt,
+}
+
+var (
+	// include is a repeatable flag with the glob patterns of the test files to list.
+	include stringList
+
+	// exclude is a repeatable flag with the glob patterns of the test files to skip.
+	exclude stringList
 )
 
 var (
@@ -30,36 +157,232 @@ var (
 	// errNotAFile is the error message when the user provides a directory as a file.
 	errNotAFile = errors.New("ERROR: required file, provided directory")
 
+	// errWorkspaceArgs is the error message when the user provides a file or directories with the workspace flag.
+	errWorkspaceArgs = errors.New("ERROR: cannot specify a file or directories with the workspace flag")
+
+	// errNoWorkspace is the error message when the workspace flag is used outside a go workspace.
+	errNoWorkspace = errors.New("ERROR: no go.work file found")
+
+	// errFormat is the error message when the user provides an unknown output format.
+	errFormat = errors.New("ERROR: unknown output format, expected one of json, ndjson, text, table, template or junit")
+
+	// errTemplateArgs is the error message when the template format is used without a template.
+	errTemplateArgs = errors.New("ERROR: the template format requires the --template or --template-file flag")
+
+	// errTemplate is the error message when the template can't be read, parsed or executed.
+	errTemplate = errors.New("ERROR: invalid template")
+
+	// errStreamFormat is the error message when the user streams the tests in a format other than JSON or NDJSON.
+	errStreamFormat = errors.New("ERROR: only the json and ndjson formats can be streamed")
+
+	// errShardArgs is the error message when the shard command is used without a valid number of shards or index.
+	errShardArgs = errors.New("ERROR: the shard command requires --total greater than 0 and --index between 0 and total-1")
+
+	// errTimingsArgs is the error message when the ingest command is used without a timing file.
+	errTimingsArgs = errors.New("ERROR: the ingest command requires the --timings flag")
+
+	// errKind is the error message when the user provides an unknown kind of test.
+	errKind = errors.New("ERROR: unknown kind, expected one of test, benchmark, example or fuzz")
+
+	// errCommandFormat is the error message when the user provides a format other than JSON to a command which
+	// only writes JSON.
+	errCommandFormat = errors.New("ERROR: the compare, verify, run and at commands only write the json format")
+
+	// errLocation is the error message when the at command is not used with a single valid location.
+	errLocation = errors.New("ERROR: the at command requires a single file:line[:column] location")
+
+	// errDiagnostics is the error message when syntax errors are found in strict mode.
+	errDiagnostics = errors.New("ERROR: found syntax errors in the test files")
+
 	// errUnknown is the error message when the error is not an expected type.
 	errUnknown = errors.New("ERROR: unknown error")
 )
 
 func main() {
 	flag.Usage = printHelpText(flag.CommandLine.Output())
+	flag.Var(&include, "include", "glob pattern of the test files to list")
+	flag.Var(&exclude, "exclude", "glob pattern of the test files to skip")
 	flag.Parse()
 
+	// the flags provided after the command are parsed again.
+	var command string
+
+	for _, c := range commands {
+		if flag.Arg(0) == c {
+			command = c
+			_ = flag.CommandLine.Parse(flag.Args()[1:])
+
+			break
+		}
+	}
+
 	err := Process(&args{
-		file:   *file,
-		dirs:   flag.Args(),
-		help:   *help,
-		pretty: *pretty,
+		command:       command,
+		file:          *file,
+		dirs:          flag.Args(),
+		help:          *help,
+		pretty:        *pretty,
+		tags:          splitList(*tags),
+		run:           *runPattern,
+		skip:          *skipPattern,
+		kinds:         splitList(*kinds),
+		maxDepth:      *maxDepth,
+		workspace:     *workspace,
+		include:       include,
+		exclude:       exclude,
+		noIgnore:      *noIgnore,
+		skipGenerated: *skipGenerated,
+		workers:       *workers,
+		tolerant:      *tolerant,
+		strict:        *strict,
+		buildTags:     splitList(*buildTags),
+		baseDir:       *baseDir,
+		timeout:       *timeout,
+		stream:        *stream,
+		cacheDir:      cacheDirectory(*cacheDir, *useCache, *noCache),
+		interval:      *interval,
+		format:        *format,
+		color:         useColor(os.Stdout),
+		template:      *templateText,
+		templateFile:  *templateFile,
+		input:         os.Stdin,
+		stderr:        os.Stderr,
+		total:         *total,
+		index:         *index,
+		timings:       *timings,
+		testLog:       *testLog,
+		changedSince:  *changedSince,
 	}, os.Stdout)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitCode(err))
 	}
 }
 
 // args is a struct that contains the arguments provided by the user.
 type args struct {
-	file   string
-	dirs   []string
-	help   bool
-	pretty bool
+	command   string
+	file      string
+	dirs      []string
+	help      bool
+	pretty    bool
+	tags      []string
+	workspace bool
+
+	run      string
+	skip     string
+	kinds    []string
+	maxDepth int
+
+	include       []string
+	exclude       []string
+	noIgnore      bool
+	skipGenerated bool
+	workers       int
+	tolerant      bool
+	strict        bool
+	buildTags     []string
+	baseDir       string
+	timeout       time.Duration
+	stream        bool
+	cacheDir      string
+	interval      time.Duration
+	format        string
+	color         bool
+	template      string
+	templateFile  string
+	input         io.Reader
+	stderr        io.Writer
+	total         int
+	index         int
+	timings       string
+	testLog       string
+	changedSince  string
+}
+
+// options returns the options used to list the tests based on the arguments provided by the user.
+func (a *args) options() pkg.Options {
+	opts := pkg.Options{
+		Patterns:       a.dirs,
+		BaseDir:        a.baseDir,
+		Tags:           a.tags,
+		Run:            a.run,
+		Skip:           a.skip,
+		MaxDepth:       a.maxDepth,
+		Include:        a.include,
+		Exclude:        a.exclude,
+		IncludeIgnored: a.noIgnore,
+		SkipGenerated:  a.skipGenerated,
+		Workers:        a.workers,
+		Tolerant:       a.tolerant,
+		CacheDir:       a.cacheDir,
+	}
+
+	if a.file != "" {
+		opts.Patterns = append(opts.Patterns, a.file)
+	}
+
+	if len(a.buildTags) > 0 {
+		opts.BuildTags = a.buildTags
+	}
+
+	for _, kind := range a.kinds {
+		opts.Kinds = append(opts.Kinds, pkg.Kind(kind))
+	}
+
+	return opts
+}
+
+// cacheDirectory returns the directory of the cache, the cache is only used if the user enabled it or provided
+// a directory, the default cache directory is used if the user did not provide one. It returns an empty string,
+// which disables the cache, if the user did not enable it, disabled it or if the default cache directory is not
+// available.
+func cacheDirectory(dir string, enabled, disabled bool) string {
+	if disabled {
+		return ""
+	}
+
+	if dir != "" {
+		return dir
+	}
+
+	if !enabled {
+		return ""
+	}
+
+	dir, err := pkg.DefaultCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return dir
+}
+
+// stringList is a flag value which can be provided multiple times.
+type stringList []string
+
+// String returns the values of the flag separated by commas.
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set adds the given value to the list.
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+
+	return nil
 }
 
 // Process is the main function that processes the arguments and prints the output.
 func Process(proc *args, writer io.Writer) error {
+	if proc.command == commandPattern && !proc.help {
+		return writePattern(proc, writer)
+	}
+
+	if proc.command == commandIngest && !proc.help {
+		return ingestTimings(proc, writer)
+	}
+
 	if requiresHelp(proc) {
 		printHelpText(writer)()
 
@@ -70,47 +393,432 @@ func Process(proc *args, writer io.Writer) error {
 		return err
 	}
 
-	if proc.file != "" {
-		proc.dirs = append(proc.dirs, proc.file)
+	if proc.command == commandWatch {
+		return watchTests(proc, writer)
+	}
+
+	if proc.command == commandShard {
+		return writeShard(proc, writer)
 	}
 
-	tests, err := pkg.List(proc.dirs)
+	if proc.command == commandCompare {
+		return compareTests(proc, writer)
+	}
+
+	if proc.command == commandVerify {
+		return verifyTests(proc, writer)
+	}
+
+	if proc.command == commandRun {
+		return runTests(proc, writer)
+	}
+
+	if proc.command == commandAt {
+		return findTestAt(proc, writer)
+	}
+
+	if proc.format == formatNDJSON {
+		return writeNDJSON(proc, writer)
+	}
+
+	if proc.stream {
+		return streamTests(proc, writer)
+	}
+
+	var tmpl *template.Template
+
+	if proc.format == formatTemplate {
+		var err error
+		if tmpl, err = parseTemplate(proc.template, proc.templateFile); err != nil {
+			return err
+		}
+	}
+
+	result, err := listTests(proc)
 	if err != nil {
-		return fmt.Errorf("%s: %w", errUnknown, err)
+		return err
 	}
 
-	if len(tests) == 0 {
-		_, _ = writer.Write([]byte("No tests found\n"))
+	if len(result.Tests) == 0 && len(result.Diagnostics) == 0 {
+		noTestsFound(proc, writer)
 
-		return nil
+		// an empty JUnit test plan is still written, the tools reading it fail on an empty file.
+		if proc.format != formatJUnit {
+			return nil
+		}
+	}
+
+	switch proc.format {
+	case formatText:
+		err = writeText(writer, result.Tests)
+		writeDiagnostics(proc.errorWriter(), result.Diagnostics)
+	case formatTable:
+		err = writeTable(writer, result.Tests, proc.color)
+		writeDiagnostics(proc.errorWriter(), result.Diagnostics)
+	case formatTemplate:
+		err = writeTemplate(writer, tmpl, result.Tests)
+		writeDiagnostics(proc.errorWriter(), result.Diagnostics)
+	case formatJUnit:
+		err = writeJUnit(writer, result.Tests)
+		writeDiagnostics(proc.errorWriter(), result.Diagnostics)
+	default:
+		err = writeJSON(proc, writer, result)
 	}
 
-	marshal, err := json.Marshal(tests)
+	if err != nil {
+		return err
+	}
+
+	if proc.strict && len(result.Diagnostics) > 0 {
+		return fmt.Errorf("%w: %d syntax errors", errDiagnostics, len(result.Diagnostics))
+	}
+
+	return nil
+}
+
+// writeJSON writes the tests in JSON format, pretty printed if requested by the user.
+// In tolerant mode the diagnostics are reported along with the tests.
+func writeJSON(proc *args, writer io.Writer, result *pkg.Result) error {
+	var output interface{} = result.Tests
+	if proc.tolerant {
+		if result.Tests == nil {
+			result.Tests = []pkg.TestDetail{}
+		}
+
+		output = result
+	}
+
+	marshal, err := json.Marshal(output)
 	if err != nil {
 		return fmt.Errorf("%s: %w", errUnknown, err)
 	}
 
 	if proc.pretty {
-		if err := prettyPrint(marshal, writer); err != nil {
-			return err
+		return prettyPrint(marshal, writer)
+	}
+
+	_, _ = writer.Write(marshal)
+
+	return nil
+}
+
+// listTests lists the tests in the file, directories or the go workspace provided by the user.
+// The listing is cancelled on interrupt or when the timeout provided by the user expires.
+func listTests(proc *args) (*pkg.Result, error) {
+	opts, err := proc.listOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	result, err := pkg.ListWithOptions(ctx, opts)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return result, nil
+}
+
+// streamTests writes the tests of each file, as soon as the file is parsed, as a JSON object with the `tests` and
+// the `diagnostics` of the file on its own line.
+func streamTests(proc *args, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+
+	return streamResults(proc, writer, func(result pkg.Result) error {
+		if result.Tests == nil {
+			result.Tests = []pkg.TestDetail{}
+		}
+
+		return encoder.Encode(result)
+	})
+}
+
+// writeNDJSON writes each test, as soon as its file is parsed, as a JSON object on its own line.
+// The diagnostics are written to stderr.
+func writeNDJSON(proc *args, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+
+	return streamResults(proc, writer, func(result pkg.Result) error {
+		for _, test := range result.Tests {
+			if err := encoder.Encode(test); err != nil {
+				return err
+			}
+		}
+
+		writeDiagnostics(proc.errorWriter(), result.Diagnostics)
+
+		return nil
+	})
+}
+
+// streamResults lists the tests with the streaming API and calls write with the result of each file as soon as
+// the file is parsed, so the tests are never all held in memory.
+func streamResults(proc *args, writer io.Writer, write func(pkg.Result) error) error {
+	opts, err := proc.listOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	var (
+		found       = false
+		diagnostics = 0
+	)
+
+	err = pkg.ListFunc(ctx, opts, func(result pkg.Result) error {
+		found = true
+		diagnostics += len(result.Diagnostics)
+
+		if err := write(result); err != nil {
+			return fmt.Errorf("%s: %w", errUnknown, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if !found {
+		noTestsFound(proc, writer)
+	}
+
+	if proc.strict && diagnostics > 0 {
+		return fmt.Errorf("%w: %d syntax errors", errDiagnostics, diagnostics)
+	}
+
+	return nil
+}
+
+// noTestsFound reports that no test was found. The message is written to the output in JSON format, the other
+// formats are read by other tools and the message is written to stderr instead.
+func noTestsFound(proc *args, writer io.Writer) {
+	if proc.format != "" && proc.format != formatJSON {
+		writer = proc.errorWriter()
+	}
+
+	_, _ = writer.Write([]byte("No tests found\n"))
+}
+
+// watchTests watches the tests and writes the events describing how they change as JSON objects, one per line.
+// Watching stops without an error on interrupt or when the timeout provided by the user expires.
+func watchTests(proc *args, writer io.Writer) error {
+	opts, err := proc.listOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	encoder := json.NewEncoder(writer)
+
+	interval := proc.interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	err = pkg.Watch(ctx, opts, interval, func(event pkg.Event) error {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("%s: %w", errUnknown, err)
+		}
+
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// writePattern writes the minimal `go test -run` pattern matching the tests whose names are provided as
+// arguments or, without arguments, read one per line from the input.
+func writePattern(proc *args, writer io.Writer) error {
+	names := proc.dirs
+
+	if len(names) == 0 && proc.input != nil {
+		scanner := bufio.NewScanner(proc.input)
+		for scanner.Scan() {
+			if name := strings.TrimSpace(scanner.Text()); name != "" {
+				names = append(names, name)
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("%s: %w", errUnknown, err)
+		}
+	}
+
+	if pattern := pkg.CombinePatterns(names); pattern != "" {
+		if _, err := fmt.Fprintln(writer, pattern); err != nil {
+			return fmt.Errorf("%s: %w", errUnknown, err)
 		}
-	} else {
-		_, _ = writer.Write(marshal)
 	}
 
 	return nil
 }
 
+// listOptions returns the options used to list the tests, with the go workspace of the current directory if the
+// user requested the workspace, the timings of the timing file provided by the user and the changes of the git
+// repository of the current directory since the ref provided by the user.
+func (a *args) listOptions() (pkg.Options, error) {
+	opts := a.options()
+
+	if a.workspace {
+		if opts.Workspace = pkg.FindWorkspace("."); opts.Workspace == "" {
+			return opts, errNoWorkspace
+		}
+	}
+
+	// the timing file is only read once it exists by the ingest command.
+	if a.timings != "" && a.command != commandIngest {
+		timings, err := pkg.ReadTimings(a.timings)
+		if err != nil {
+			return opts, wrapError(err)
+		}
+
+		opts.Timings = timings
+	}
+
+	if a.changedSince != "" {
+		ctx, cancel := a.context()
+		defer cancel()
+
+		opts.Changes = make(pkg.Changes)
+
+		for _, dir := range a.changedDirs(opts) {
+			changes, err := pkg.GitChanges(ctx, dir, a.changedSince)
+			if err != nil {
+				return opts, wrapError(err)
+			}
+
+			for path, ranges := range changes {
+				opts.Changes[path] = ranges
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// changedDirs returns the directories the git changes are read from, one for each file, directory or package
+// pattern listed. The repository containing each directory is used, so the listed directories may belong to
+// different repositories. The import paths are resolved by the go tool from the current directory.
+func (a *args) changedDirs(opts pkg.Options) []string {
+	var (
+		dirs []string
+		seen = make(map[string]bool)
+	)
+
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if opts.Workspace != "" {
+		add(filepath.Dir(opts.Workspace))
+	}
+
+	for _, pattern := range opts.Patterns {
+		// the directory of a pattern with a wildcard contains all the directories it matches.
+		dir := pattern
+		if before, _, ok := strings.Cut(pattern, "..."); ok {
+			dir = filepath.Dir(before + "_")
+		}
+
+		stat, err := os.Stat(dir)
+
+		switch {
+		case err != nil:
+			add(".")
+		case stat.IsDir():
+			add(dir)
+		default:
+			add(filepath.Dir(dir))
+		}
+	}
+
+	return dirs
+}
+
+// context returns the context of the listing, it is cancelled on interrupt or when the timeout provided by the
+// user expires.
+func (a *args) context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if a.timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// errorWriter returns the writer of the diagnostics and the messages which are not part of the output, the
+// diagnostics are discarded if no writer is provided.
+func (a *args) errorWriter() io.Writer {
+	if a.stderr == nil {
+		return io.Discard
+	}
+
+	return a.stderr
+}
+
 // validateArgs validates the arguments provided by the user.
 func validateArgs(args *args) error {
 	if args.file != "" && len(args.dirs) > 0 {
 		return errPathIssue
 	}
 
+	if args.workspace && (args.file != "" || len(args.dirs) > 0) {
+		return errWorkspaceArgs
+	}
+
+	if args.command == commandShard && (args.total < 1 || args.index < 0 || args.index >= args.total) {
+		return errShardArgs
+	}
+
+	if args.command == commandAt && (len(args.dirs) != 1 || args.file != "" || args.workspace) {
+		return errLocation
+	}
+
+	if !isValidFormat(args.format) {
+		return errFormat
+	}
+
+	switch args.command {
+	case commandCompare, commandVerify, commandRun, commandAt:
+		if args.format != "" && args.format != formatJSON {
+			return errCommandFormat
+		}
+	}
+
+	for _, kind := range args.kinds {
+		switch pkg.Kind(kind) {
+		case pkg.KindTest, pkg.KindBenchmark, pkg.KindExample, pkg.KindFuzz:
+		default:
+			return errKind
+		}
+	}
+
+	if args.stream && args.format != "" && args.format != formatJSON && args.format != formatNDJSON {
+		return errStreamFormat
+	}
+
 	if args.file != "" {
 		stat, err := os.Stat(args.file)
 		if err != nil {
-			return fmt.Errorf("%s: %w", errUnknown, err)
+			return wrapError(&pkg.WalkError{Path: args.file, Err: err})
 		}
 
 		if stat.IsDir() {
@@ -123,7 +831,20 @@ func validateArgs(args *args) error {
 
 // requiresHelp checks if the user has requested help and not provided any required arguments.
 func requiresHelp(proc *args) bool {
-	return (len(proc.dirs) == 0 && proc.file == "") || proc.help
+	return (len(proc.dirs) == 0 && proc.file == "" && !proc.workspace) || proc.help
+}
+
+// splitList splits the comma separated list provided by the user and drops the empty values.
+func splitList(value string) []string {
+	var values []string
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
 }
 
 // prettyPrint prints the given json in a pretty format.
@@ -148,19 +869,73 @@ func printHelpText(writer io.Writer) func() {
 		_, _ = fmt.Fprintf(writer, `gotest-ls provides a list of all tests in a package or a file in JSON format.
 
 Usage:
-  gotest-ls [flags] [directories]
+  gotest-ls [flags] [directories|packages]
+  gotest-ls watch [flags] [directories|packages]
+  gotest-ls pattern [test names]
+  gotest-ls shard --total N --index i [flags] [directories|packages]
+  gotest-ls ingest --timings path [flags] [go test -json files]
+  gotest-ls compare [--log path] [flags] [directories|packages]
+  gotest-ls verify [flags] [directories|packages]
+  gotest-ls run [flags] [directories|packages]
+  gotest-ls at [flags] file:line[:column]
 
 Examples:
 	gotest-ls .
+ 	gotest-ls ./...
+ 	gotest-ls github.com/acme/svc/...
  	gotest-ls -p ./cmd
  	gotest-ls -p ./cmd ./pkg
  	gotest-ls -f ./pkg/random_test.go
  	gotest-ls -p -f ./pkg/random_test.go
+ 	gotest-ls -tags slow,owner=payments ./pkg
+ 	gotest-ls -w
+ 	gotest-ls --exclude '**/mocks/**' --skip-generated ./...
+ 	gotest-ls --run 'Sum/^negative' --skip Slow --kind test ./...
+ 	gotest-ls --changed-since origin/main --format text ./...
+ 	gotest-ls watch --interval 2s ./...
+ 	gotest-ls --format text ./pkg | grep Sum | gotest-ls pattern
+ 	gotest-ls shard --total 12 --index 3 --timings timings.json ./...
+ 	go test -json ./... | gotest-ls ingest --timings timings.json
+ 	gotest-ls compare --log test.json -p ./...
+ 	gotest-ls verify ./...
+ 	gotest-ls run -tags slow ./...
+ 	gotest-ls at ./pkg/random_test.go:42:7
 
 Flags:
   -f, --file string   Path to a file, cannot be used with directories
   -h, --help          help for gotest-ls
+  -j int              Number of files parsed concurrently, defaults to GOMAXPROCS
   -p, --pretty        Pretty print the output in JSON format
+  --format string     Output format: json, ndjson (one test per line), text (one test name per line), table,
+                      template or junit (JUnit XML test plan), defaults to json
+  --template string   Go template executed for each test by the template format
+  --template-file path File containing the Go template executed for each test by the template format
+  -w, --workspace     List the tests of all the modules in the go.work workspace
+  --tags string       Comma separated tags, only tests annotated with all of them are listed
+  --run regexp        Only list the tests selected by the go test -run pattern, matched level by level
+  --skip regexp       Skip the tests matching the go test -skip pattern
+  --kind string       Comma separated kinds of the tests to list: test, benchmark, example or fuzz
+  --max-depth int     Only list the tests nested in at most the given levels, 1 for the top level tests
+  --include glob      Only list the test files matching the glob, can be repeated
+  --exclude glob      Skip the test files matching the glob, can be repeated
+  --no-ignore         Do not honour the .gitignore and .ignore files
+  --skip-generated    Skip the generated test files
+  --tolerant          Report syntax errors as diagnostics and list the tests of the parseable parts
+  --strict            Exit with an error if diagnostics are reported in tolerant mode
+  --build-tags string Comma separated build tags, only files matching their build constraints are listed
+  --base string       Directory the relative paths are computed from
+  --timeout duration  Stop listing the tests after the given duration
+  --stream            Write the tests of each file as soon as it is parsed, one JSON object per line
+  --cache             Cache the result of listing each file in gotest-ls in the user cache directory
+  --no-cache          Parse all the files without reading or writing the cache, even with --cache
+  --cache-dir string  Directory of the cache, enables the cache in the given directory
+  --interval duration Interval between two polls of the watch command, defaults to 1s
+  --total int         Number of shards of the shard command
+  --index int         Zero-based index of the shard printed by the shard command
+  --timings path      Timing file with the average durations of the tests, attached as avgDuration, weighting
+                      the shards and updated by the ingest command
+  --log path          go test -json log of the compare command, defaults to the standard input
+  --changed-since ref Only list the tests changed since the git ref, including the uncommitted changes
 `)
 	}
 }
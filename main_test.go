@@ -2,12 +2,16 @@ package main
 
 import (
 	"bytes"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ninadingole/gotest-ls/pkg"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,32 +32,42 @@ func TestListAllTestsForGivenFile(t *testing.T) {
 	fmt.Println(buffer.String())
 
 	require.JSONEq(t,
-		strings.ReplaceAll(`[{"name":"BenchmarkSomething","fileName":"benchmark_test.go","relativePath":"tests/benchmark_test.go","absolutePath":"##PATH##/tests/benchmark_test.go","line":5,"pos":44},{"name":"Example_something","fileName":"example_test.go","relativePath":"tests/example_test.go","absolutePath":"##PATH##/tests/example_test.go","line":5,"pos":40},{"name":"Test/5_+_5_=_10","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":23,"pos":265},{"name":"Test/5_-_5_=_0","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":30,"pos":355},{"name":"Test/mixed_subtest_1","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":12,"pos":111},{"name":"Test/mixed_test_2","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":48,"pos":635},{"name":"TestSomething","fileName":"sample_test.go","relativePath":"tests/sample_test.go","absolutePath":"##PATH##/tests/sample_test.go","line":7,"pos":49},{"name":"Test_subTestPattern/subtest","fileName":"subtest_test.go","relativePath":"tests/subtest_test.go","absolutePath":"##PATH##/tests/subtest_test.go","line":10,"pos":121},{"name":"Test_subTestPattern/subtest_2","fileName":"subtest_test.go","relativePath":"tests/subtest_test.go","absolutePath":"##PATH##/tests/subtest_test.go","line":15,"pos":193}]`,
+		strings.ReplaceAll(`[{"name":"BenchmarkSomething","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"benchmark_test.go","relativePath":"tests/benchmark_test.go","absolutePath":"##PATH##/tests/benchmark_test.go","line":5,"pos":44,"benchPattern":"^BenchmarkSomething$","command":["go","test","-run","^$","-bench","^BenchmarkSomething$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Example_something","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"example_test.go","relativePath":"tests/example_test.go","absolutePath":"##PATH##/tests/example_test.go","line":5,"pos":40,"runPattern":"^Example_something$","command":["go","test","-run","^Example_something$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/5_+_5_=_10","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":23,"pos":265,"runPattern":"^Test$/^5_\\+_5_=_10$","command":["go","test","-run","^Test$/^5_\\+_5_=_10$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/5_-_5_=_0","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":30,"pos":355,"runPattern":"^Test$/^5_-_5_=_0$","command":["go","test","-run","^Test$/^5_-_5_=_0$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/mixed_subtest_1","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":12,"pos":111,"runPattern":"^Test$/^mixed_subtest_1$","command":["go","test","-run","^Test$/^mixed_subtest_1$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/mixed_test_2","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"tests/table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":48,"pos":635,"runPattern":"^Test$/^mixed_test_2$","command":["go","test","-run","^Test$/^mixed_test_2$","github.com/ninadingole/gotest-ls/tests"]},{"name":"TestSomething","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"sample_test.go","relativePath":"tests/sample_test.go","absolutePath":"##PATH##/tests/sample_test.go","line":7,"pos":49,"runPattern":"^TestSomething$","command":["go","test","-run","^TestSomething$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test_subTestPattern/subtest","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"subtest_test.go","relativePath":"tests/subtest_test.go","absolutePath":"##PATH##/tests/subtest_test.go","line":10,"pos":121,"runPattern":"^Test_subTestPattern$/^subtest$","command":["go","test","-run","^Test_subTestPattern$/^subtest$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test_subTestPattern/subtest_2","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"subtest_test.go","relativePath":"tests/subtest_test.go","absolutePath":"##PATH##/tests/subtest_test.go","line":15,"pos":193,"runPattern":"^Test_subTestPattern$/^subtest_2$","command":["go","test","-run","^Test_subTestPattern$/^subtest_2$","github.com/ninadingole/gotest-ls/tests"]}]`,
 			"##PATH##", pwd),
 		buffer.String())
 }
 
 func Example_errorIfFileAndDirectoryBothAreProvided() {
-	cmd := exec.Command("go", "run", "main.go", "-p", "-f", "./tests/sample_test.go", "./tests")
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("go", "run", ".", "-p", "-f", "./tests/sample_test.go", "./tests")
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err == nil {
 		panic("expected error")
 	}
 
+	// the error is written to stderr, followed by the exit status reported by go run.
+	fmt.Print(strings.SplitAfter(stderr.String(), "\n")[0])
+
 	// Output: ERROR: cannot specify both a file and a directory
 }
 
 func Example_errorIfFileProvidedIsDirectory() {
-	cmd := exec.Command("go", "run", "main.go", "-p", "-f", "./tests")
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("go", "run", ".", "-p", "-f", "./tests")
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err == nil {
 		panic("expected error")
 	}
 
+	// the error is written to stderr, followed by the exit status reported by go run.
+	fmt.Print(strings.SplitAfter(stderr.String(), "\n")[0])
+
 	// Output: ERROR: required file, provided directory
 }
 
@@ -63,6 +77,9 @@ func Test_process(t *testing.T) {
 	pwd, err := os.Getwd()
 	require.NoError(t, err)
 
+	brokenFile := filepath.Join(t.TempDir(), "broken_test.go")
+	require.NoError(t, os.WriteFile(brokenFile, []byte("package tests_test\n\ndummy dummy test\n"), os.ModePerm))
+
 	tests := []struct {
 		name        string
 		args        args
@@ -89,6 +106,15 @@ func Test_process(t *testing.T) {
 			wantErr:     true,
 			errExpected: errNotAFile.Error(),
 		},
+		{
+			name: "should return error if directories are provided with the workspace flag",
+			args: args{
+				workspace: true,
+				dirs:      []string{"./tests"},
+			},
+			wantErr:     true,
+			errExpected: errWorkspaceArgs.Error(),
+		},
 		{
 			name: "should return the test details in a file",
 			args: args{
@@ -98,7 +124,7 @@ func Test_process(t *testing.T) {
 			checks: func(t *testing.T, got string) {
 				t.Helper()
 
-				require.JSONEq(t, fmt.Sprintf(`[{"name":"TestSomething","fileName":"sample_test.go","relativePath":"sample_test.go","absolutePath":"%s/tests/sample_test.go","line":7,"pos":49}]`, pwd),
+				require.JSONEq(t, fmt.Sprintf(`[{"name":"TestSomething","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"%[1]s","fileName":"sample_test.go","relativePath":"sample_test.go","absolutePath":"%[1]s/tests/sample_test.go","line":7,"pos":49,"runPattern":"^TestSomething$","command":["go","test","-run","^TestSomething$","github.com/ninadingole/gotest-ls/tests"]}]`, pwd),
 					got)
 			},
 		},
@@ -114,11 +140,22 @@ func Test_process(t *testing.T) {
 				require.JSONEq(t, fmt.Sprintf(`[
 	{
 		"name": "TestSomething",
+		"package": "github.com/ninadingole/gotest-ls/tests",
+		"module": "github.com/ninadingole/gotest-ls",
+		"moduleRoot": "%[1]s",
 		"fileName": "sample_test.go",
 		"relativePath": "sample_test.go",
-		"absolutePath": "%s/tests/sample_test.go",
+		"absolutePath": "%[1]s/tests/sample_test.go",
 		"line": 7,
-		"pos": 49
+		"pos": 49,
+		"runPattern": "^TestSomething$",
+		"command": [
+			"go",
+			"test",
+			"-run",
+			"^TestSomething$",
+			"github.com/ninadingole/gotest-ls/tests"
+		]
 	}
 ]`, pwd), got)
 			},
@@ -132,7 +169,7 @@ func Test_process(t *testing.T) {
 			checks: func(t *testing.T, got string) {
 				t.Helper()
 
-				require.JSONEq(t, strings.ReplaceAll(`[{"name":"Test/5_+_5_=_10","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":23,"pos":265},{"name":"Test/5_-_5_=_0","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":30,"pos":355},{"name":"Test/mixed_subtest_1","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":12,"pos":111},{"name":"Test/mixed_test_2","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":48,"pos":635}]`, "##PATH##", pwd), got)
+				require.JSONEq(t, strings.ReplaceAll(`[{"name":"Test/5_+_5_=_10","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":23,"pos":265,"runPattern":"^Test$/^5_\\+_5_=_10$","command":["go","test","-run","^Test$/^5_\\+_5_=_10$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/5_-_5_=_0","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":30,"pos":355,"runPattern":"^Test$/^5_-_5_=_0$","command":["go","test","-run","^Test$/^5_-_5_=_0$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/mixed_subtest_1","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":12,"pos":111,"runPattern":"^Test$/^mixed_subtest_1$","command":["go","test","-run","^Test$/^mixed_subtest_1$","github.com/ninadingole/gotest-ls/tests"]},{"name":"Test/mixed_test_2","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"##PATH##","fileName":"table_test.go","relativePath":"table_test.go","absolutePath":"##PATH##/tests/table_test.go","line":48,"pos":635,"runPattern":"^Test$/^mixed_test_2$","command":["go","test","-run","^Test$/^mixed_test_2$","github.com/ninadingole/gotest-ls/tests"]}]`, "##PATH##", pwd), got)
 			},
 		},
 		{
@@ -144,19 +181,73 @@ func Test_process(t *testing.T) {
 				require.Equal(t, `gotest-ls provides a list of all tests in a package or a file in JSON format.
 
 Usage:
-  gotest-ls [flags] [directories]
+  gotest-ls [flags] [directories|packages]
+  gotest-ls watch [flags] [directories|packages]
+  gotest-ls pattern [test names]
+  gotest-ls shard --total N --index i [flags] [directories|packages]
+  gotest-ls ingest --timings path [flags] [go test -json files]
+  gotest-ls compare [--log path] [flags] [directories|packages]
+  gotest-ls verify [flags] [directories|packages]
+  gotest-ls run [flags] [directories|packages]
+  gotest-ls at [flags] file:line[:column]
 
 Examples:
 	gotest-ls .
+ 	gotest-ls ./...
+ 	gotest-ls github.com/acme/svc/...
  	gotest-ls -p ./cmd
  	gotest-ls -p ./cmd ./pkg
  	gotest-ls -f ./pkg/random_test.go
  	gotest-ls -p -f ./pkg/random_test.go
+ 	gotest-ls -tags slow,owner=payments ./pkg
+ 	gotest-ls -w
+ 	gotest-ls --exclude '**/mocks/**' --skip-generated ./...
+ 	gotest-ls --run 'Sum/^negative' --skip Slow --kind test ./...
+ 	gotest-ls --changed-since origin/main --format text ./...
+ 	gotest-ls watch --interval 2s ./...
+ 	gotest-ls --format text ./pkg | grep Sum | gotest-ls pattern
+ 	gotest-ls shard --total 12 --index 3 --timings timings.json ./...
+ 	go test -json ./... | gotest-ls ingest --timings timings.json
+ 	gotest-ls compare --log test.json -p ./...
+ 	gotest-ls verify ./...
+ 	gotest-ls run -tags slow ./...
+ 	gotest-ls at ./pkg/random_test.go:42:7
 
 Flags:
   -f, --file string   Path to a file, cannot be used with directories
   -h, --help          help for gotest-ls
+  -j int              Number of files parsed concurrently, defaults to GOMAXPROCS
   -p, --pretty        Pretty print the output in JSON format
+  --format string     Output format: json, ndjson (one test per line), text (one test name per line), table,
+                      template or junit (JUnit XML test plan), defaults to json
+  --template string   Go template executed for each test by the template format
+  --template-file path File containing the Go template executed for each test by the template format
+  -w, --workspace     List the tests of all the modules in the go.work workspace
+  --tags string       Comma separated tags, only tests annotated with all of them are listed
+  --run regexp        Only list the tests selected by the go test -run pattern, matched level by level
+  --skip regexp       Skip the tests matching the go test -skip pattern
+  --kind string       Comma separated kinds of the tests to list: test, benchmark, example or fuzz
+  --max-depth int     Only list the tests nested in at most the given levels, 1 for the top level tests
+  --include glob      Only list the test files matching the glob, can be repeated
+  --exclude glob      Skip the test files matching the glob, can be repeated
+  --no-ignore         Do not honour the .gitignore and .ignore files
+  --skip-generated    Skip the generated test files
+  --tolerant          Report syntax errors as diagnostics and list the tests of the parseable parts
+  --strict            Exit with an error if diagnostics are reported in tolerant mode
+  --build-tags string Comma separated build tags, only files matching their build constraints are listed
+  --base string       Directory the relative paths are computed from
+  --timeout duration  Stop listing the tests after the given duration
+  --stream            Write the tests of each file as soon as it is parsed, one JSON object per line
+  --cache             Cache the result of listing each file in gotest-ls in the user cache directory
+  --no-cache          Parse all the files without reading or writing the cache, even with --cache
+  --cache-dir string  Directory of the cache, enables the cache in the given directory
+  --interval duration Interval between two polls of the watch command, defaults to 1s
+  --total int         Number of shards of the shard command
+  --index int         Zero-based index of the shard printed by the shard command
+  --timings path      Timing file with the average durations of the tests, attached as avgDuration, weighting
+                      the shards and updated by the ingest command
+  --log path          go test -json log of the compare command, defaults to the standard input
+  --changed-since ref Only list the tests changed since the git ref, including the uncommitted changes
 `, got)
 			},
 		},
@@ -166,7 +257,277 @@ Flags:
 				dirs: []string{"./false-directory"},
 			},
 			wantErr:     true,
-			errExpected: errUnknown.Error() + ": lstat ./false-directory: no such file or directory",
+			errExpected: errWalk.Error() + ": walk ./false-directory: lstat ./false-directory: no such file or directory",
+		},
+		{
+			name: "should filter the tests by tags",
+			args: args{
+				dirs: []string{"./tests"},
+				tags: []string{"slow"},
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "No tests found\n", got)
+			},
+		},
+		{
+			name: "should report syntax errors as diagnostics in tolerant mode",
+			args: args{
+				file:     brokenFile,
+				tolerant: true,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.JSONEq(t, fmt.Sprintf(`{"tests":[],"diagnostics":[{"file":"%s","line":3,"column":1,"message":"expected declaration, found dummy"}]}`, brokenFile), got)
+			},
+		},
+		{
+			name: "should return error for diagnostics in strict mode",
+			args: args{
+				file:     brokenFile,
+				tolerant: true,
+				strict:   true,
+			},
+			wantErr:     true,
+			errExpected: errDiagnostics.Error() + ": 1 syntax errors",
+		},
+		{
+			name: "should stream the tests of each file",
+			args: args{
+				file:   "./tests/sample_test.go",
+				stream: true,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.True(t, strings.HasSuffix(got, "\n"))
+				require.JSONEq(t, fmt.Sprintf(`{"tests":[{"name":"TestSomething","package":"github.com/ninadingole/gotest-ls/tests","module":"github.com/ninadingole/gotest-ls","moduleRoot":"%[1]s","fileName":"sample_test.go","relativePath":"sample_test.go","absolutePath":"%[1]s/tests/sample_test.go","line":7,"pos":49,"runPattern":"^TestSomething$","command":["go","test","-run","^TestSomething$","github.com/ninadingole/gotest-ls/tests"]}]}`, pwd),
+					got)
+			},
+		},
+		{
+			name: "should stream nothing if there is no test in the directory",
+			args: args{
+				dirs:   []string{"./dead-tests"},
+				stream: true,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "No tests found\n", got)
+			},
+		},
+		{
+			name: "should watch the tests until the timeout expires",
+			args: args{
+				command:  commandWatch,
+				dirs:     []string{"./tests"},
+				interval: 10 * time.Millisecond,
+				timeout:  200 * time.Millisecond,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Contains(t, got, `{"type":"added","test":{"name":"TestSomething",`)
+				require.NotContains(t, got, `"type":"removed"`)
+			},
+		},
+		{
+			name: "should combine the test names provided as arguments into a pattern",
+			args: args{
+				command: commandPattern,
+				dirs:    []string{"Test/5_+_5_=_10", "Test/mixed_test_2", "TestSomething"},
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "^Test$/^(5_\\+_5_=_10|mixed_test_2)$|^TestSomething$\n", got)
+			},
+		},
+		{
+			name: "should combine the test names read from the input into a pattern",
+			args: args{
+				command: commandPattern,
+				input:   strings.NewReader("Test_subTestPattern/subtest\n\nTest_subTestPattern/subtest_2\n"),
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "^Test_subTestPattern$/^(subtest|subtest_2)$\n", got)
+			},
+		},
+		{
+			name: "should print the go test commands of a shard",
+			args: args{
+				command: commandShard,
+				dirs:    []string{"./tests"},
+				total:   2,
+				index:   1,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "go test -run '^(Test|Test_subTestPattern)$' github.com/ninadingole/gotest-ls/tests\n", got)
+			},
+		},
+		{
+			name: "should return error if the shard index is out of range",
+			args: args{
+				command: commandShard,
+				dirs:    []string{"./tests"},
+				total:   2,
+				index:   2,
+			},
+			wantErr:     true,
+			errExpected: errShardArgs.Error(),
+		},
+		{
+			name: "should only return the tests selected by the run and kind filters",
+			args: args{
+				dirs:   []string{"./tests"},
+				format: formatText,
+				run:    "Test$/5",
+				skip:   "Test/=_0",
+				kinds:  []string{"test"},
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "Test/5_+_5_=_10\n", got)
+			},
+		},
+		{
+			name: "should return error if the kind is unknown",
+			args: args{
+				dirs:  []string{"./tests"},
+				kinds: []string{"unit"},
+			},
+			wantErr:     true,
+			errExpected: errKind.Error(),
+		},
+		{
+			name: "should return error if the run command is used with a format other than json",
+			args: args{
+				command: commandRun,
+				dirs:    []string{"./tests"},
+				format:  formatText,
+			},
+			wantErr:     true,
+			errExpected: errCommandFormat.Error(),
+		},
+		{
+			name: "should return the test enclosing a location",
+			args: args{
+				command: commandAt,
+				dirs:    []string{"./tests/table_test.go:25"},
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Contains(t, got, `"name":"Test/5_+_5_=_10"`)
+				require.Contains(t, got, `"runPattern":"^Test$/^5_\\+_5_=_10$"`)
+			},
+		},
+		{
+			name: "should return error if the location has no line",
+			args: args{
+				command: commandAt,
+				dirs:    []string{"./tests/table_test.go"},
+			},
+			wantErr:     true,
+			errExpected: errLocation.Error(),
+		},
+		{
+			name: "should return null if no test encloses the location",
+			args: args{
+				command: commandAt,
+				dirs:    []string{"./tests/table_test.go:1"},
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "null\n", got)
+			},
+		},
+		{
+			name: "should return error if the at command is used with a format other than json",
+			args: args{
+				command: commandAt,
+				dirs:    []string{"./tests/table_test.go:25"},
+				format:  formatTable,
+			},
+			wantErr:     true,
+			errExpected: errCommandFormat.Error(),
+		},
+		{
+			name: "should return the test names in text format",
+			args: args{
+				file:   "./tests/subtest_test.go",
+				format: formatText,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "Test_subTestPattern/subtest\nTest_subTestPattern/subtest_2\n", got)
+			},
+		},
+		{
+			name: "should return one test per line in ndjson format",
+			args: args{
+				file:   "./tests/subtest_test.go",
+				format: formatNDJSON,
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+				require.Len(t, lines, 2)
+				require.Contains(t, lines[0], `"name":"Test_subTestPattern/subtest"`)
+				require.Contains(t, lines[1], `"name":"Test_subTestPattern/subtest_2"`)
+			},
+		},
+		{
+			name: "should return error when streaming the table format",
+			args: args{
+				dirs:   []string{"./tests"},
+				format: formatTable,
+				stream: true,
+			},
+			wantErr:     true,
+			errExpected: errStreamFormat.Error(),
+		},
+		{
+			name: "should execute the template for each test in template format",
+			args: args{
+				file:     "./tests/subtest_test.go",
+				format:   formatTemplate,
+				template: "{{.Name}}:{{.Line}}",
+			},
+			checks: func(t *testing.T, got string) {
+				t.Helper()
+
+				require.Equal(t, "Test_subTestPattern/subtest:10\nTest_subTestPattern/subtest_2:15\n", got)
+			},
+		},
+		{
+			name: "should return error for the template format without a template",
+			args: args{
+				dirs:   []string{"./tests"},
+				format: formatTemplate,
+			},
+			wantErr:     true,
+			errExpected: errTemplateArgs.Error(),
+		},
+		{
+			name: "should return error for an unknown format",
+			args: args{
+				dirs:   []string{"./tests"},
+				format: "yaml",
+			},
+			wantErr:     true,
+			errExpected: errFormat.Error(),
 		},
 		{
 			name: "return error if there is no test in the directory",
@@ -202,3 +563,172 @@ Flags:
 		})
 	}
 }
+
+func Test_processChangedSince(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("runs git")
+	}
+
+	var buffer bytes.Buffer
+
+	err := Process(&args{dirs: []string{"./tests"}, changedSince: "refs/heads/gotest-ls-unknown"}, &buffer)
+	require.ErrorContains(t, err, errGit.Error())
+	require.Equal(t, exitCodeWalk, exitCode(err))
+	require.Empty(t, buffer.String())
+}
+
+func Test_processChangedSinceRepositories(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil || testing.Short() {
+		t.Skip("runs git")
+	}
+
+	tmpDir := t.TempDir()
+
+	for _, repo := range []string{"a", "b"} {
+		dir := filepath.Join(tmpDir, repo)
+		writeFiles(t, dir, map[string]string{
+			"go.mod":           "module example.com/" + repo + "\n\ngo 1.19\n",
+			"app/base_test.go": "package app\n\nimport \"testing\"\n\nfunc TestBase(t *testing.T) {}\n",
+		})
+
+		cmd := exec.Command("sh", "-c", "git init -q && git add . && "+
+			"git -c user.name=gotest-ls -c user.email=gotest-ls@example.com -c commit.gpgsign=false commit -q -m base")
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+
+		writeFiles(t, dir, map[string]string{
+			"app/new_test.go": "package app\n\nimport \"testing\"\n\nfunc TestNew" + strings.ToUpper(repo) +
+				"(t *testing.T) {}\n",
+		})
+	}
+
+	var buffer bytes.Buffer
+
+	err := Process(&args{
+		dirs:         []string{filepath.Join(tmpDir, "a"), filepath.Join(tmpDir, "b", "app")},
+		changedSince: "HEAD",
+		format:       formatText,
+	}, &buffer)
+	require.NoError(t, err)
+	require.Equal(t, "TestNewA\nTestNewB\n", buffer.String())
+}
+
+func Test_changedDirs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{name: "directories", args: args{dirs: []string{"./tests", "./pkg", "./tests"}}, want: []string{"./tests", "./pkg"}},
+		{name: "wildcards", args: args{dirs: []string{"./...", "./pkg/...", "./pk..."}}, want: []string{".", "pkg"}},
+		{name: "file", args: args{file: "./tests/sample_test.go"}, want: []string{"tests"}},
+		{name: "import paths", args: args{dirs: []string{"github.com/acme/svc/..."}}, want: []string{"."}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.args.changedDirs(tt.args.options()))
+		})
+	}
+
+	workspace := filepath.Join("src", "go.work")
+	require.Equal(t, []string{"src"}, (&args{workspace: true}).changedDirs(pkg.Options{Workspace: workspace}))
+}
+
+func Test_processDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	brokenFile := filepath.Join(t.TempDir(), "broken_test.go")
+	require.NoError(t, os.WriteFile(brokenFile,
+		[]byte("package tests_test\n\nimport \"testing\"\n\nfunc TestValid(t *testing.T) {}\n\ndummy dummy test\n"),
+		os.ModePerm))
+
+	for _, format := range []string{formatText, formatNDJSON} {
+		format := format
+
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			err := Process(&args{file: brokenFile, tolerant: true, format: format, stderr: &stderr}, &stdout)
+			require.NoError(t, err)
+			require.Contains(t, stdout.String(), "TestValid")
+			require.Equal(t, brokenFile+":7:1: expected declaration, found dummy\n", stderr.String())
+		})
+	}
+}
+
+func Test_processNoTests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   args
+		stdout string
+	}{
+		{name: "text", args: args{format: formatText}},
+		{name: "table", args: args{format: formatTable}},
+		{name: "template", args: args{format: formatTemplate, template: "{{.Name}}"}},
+		{name: "ndjson", args: args{format: formatNDJSON}},
+		{name: "junit", args: args{format: formatJUnit}, stdout: xml.Header +
+			`<testsuites tests="0" skipped="0" failures="0" errors="0"></testsuites>` + "\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			tt.args.dirs = []string{"./dead-tests"}
+			tt.args.stderr = &stderr
+
+			require.NoError(t, Process(&tt.args, &stdout))
+			require.Equal(t, tt.stdout, stdout.String())
+			require.Equal(t, "No tests found\n", stderr.String())
+		})
+	}
+}
+
+func Test_kindFuzz(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod": goModFile,
+		"app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n\n" +
+			"func FuzzApp(f *testing.F) {}\n",
+	})
+
+	cmd := exec.Command("go", "run", ".", "--kind", "fuzz", "--format", "text", tmpDir)
+
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	require.Equal(t, "FuzzApp\n", string(out))
+}
+
+func Test_cacheDirectory(t *testing.T) {
+	t.Parallel()
+
+	defaultDir, err := pkg.DefaultCacheDir()
+	require.NoError(t, err)
+
+	require.Equal(t, "", cacheDirectory("", false, false))
+	require.Equal(t, "", cacheDirectory("/tmp/cache", true, true))
+	require.Equal(t, "/tmp/cache", cacheDirectory("/tmp/cache", false, false))
+	require.Equal(t, defaultDir, cacheDirectory("", true, false))
+}
@@ -0,0 +1,173 @@
+package pkg
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// annotationTagsDirective is the comment prefix used to attach tags to a test in the source code.
+// A test annotated with tags would look like this in the source code.
+//
+//	// TestPayment checks the payment flow end to end.
+//	// gotest-ls:tags slow,db owner=payments
+//	func TestPayment(t *testing.T) {}
+const annotationTagsDirective = "gotest-ls:tags"
+
+// annotations contains the doc comment and the tags parsed from the comments attached to a test.
+type annotations struct {
+	doc    string
+	tags   []string
+	labels map[string]string
+}
+
+// parseAnnotations parses the given comment groups and returns the doc comment and the tags found in them.
+// Lines starting with `gotest-ls:tags` are treated as annotations and are not part of the doc comment.
+// Tags are separated by spaces or commas, a tag in the form `key=value` is treated as a label.
+func parseAnnotations(groups ...*ast.CommentGroup) annotations {
+	var (
+		result   annotations
+		docLines []string
+	)
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, comment := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+
+			if !strings.HasPrefix(text, annotationTagsDirective) {
+				continue
+			}
+
+			result.addTags(strings.TrimPrefix(text, annotationTagsDirective))
+		}
+
+		for _, line := range strings.Split(group.Text(), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), annotationTagsDirective) {
+				continue
+			}
+
+			docLines = append(docLines, line)
+		}
+	}
+
+	result.doc = strings.TrimSpace(strings.Join(docLines, "\n"))
+
+	return result
+}
+
+// addTags parses the tags and labels from the given annotation value and adds them to the annotations.
+func (a *annotations) addTags(value string) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	for _, field := range fields {
+		if key, val, ok := strings.Cut(field, "="); ok {
+			if a.labels == nil {
+				a.labels = make(map[string]string)
+			}
+
+			a.labels[key] = val
+
+			continue
+		}
+
+		if !containsString(a.tags, field) {
+			a.tags = append(a.tags, field)
+		}
+	}
+}
+
+// inherit returns the annotations with the tags and labels of the parent added to it.
+// The doc comment of the parent is not inherited, labels defined on the child take precedence.
+func (a annotations) inherit(parent annotations) annotations {
+	result := annotations{doc: a.doc}
+
+	for _, tag := range parent.tags {
+		result.addTags(tag)
+	}
+
+	for _, tag := range a.tags {
+		result.addTags(tag)
+	}
+
+	for key, val := range parent.labels {
+		result.addTags(key + "=" + val)
+	}
+
+	for key, val := range a.labels {
+		result.addTags(key + "=" + val)
+	}
+
+	return result
+}
+
+// apply sets the doc comment, tags and labels on the given test detail.
+func (a annotations) apply(detail *TestDetail) {
+	detail.Doc = a.doc
+
+	if len(a.tags) > 0 {
+		detail.Tags = append([]string(nil), a.tags...)
+		sort.Strings(detail.Tags)
+	}
+
+	if len(a.labels) > 0 {
+		detail.Labels = make(map[string]string, len(a.labels))
+		for key, val := range a.labels {
+			detail.Labels[key] = val
+		}
+	}
+}
+
+// FilterByTags returns the tests which carry all the given tags.
+// A selector in the form `key=value` matches a label, any other selector matches a tag.
+// If no selectors are given, all the tests are returned.
+func FilterByTags(tests []TestDetail, selectors []string) []TestDetail {
+	if len(selectors) == 0 {
+		return tests
+	}
+
+	var filtered []TestDetail
+
+	for _, test := range tests {
+		if test.hasTags(selectors) {
+			filtered = append(filtered, test)
+		}
+	}
+
+	return filtered
+}
+
+// hasTags checks if the test carries all the given tag selectors.
+func (t TestDetail) hasTags(selectors []string) bool {
+	for _, selector := range selectors {
+		if key, val, ok := strings.Cut(selector, "="); ok {
+			if got, found := t.Labels[key]; !found || got != val {
+				return false
+			}
+
+			continue
+		}
+
+		if !containsString(t.Tags, selector) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsString checks if the given slice contains the given value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package pkg_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListAnnotations(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	file := fmt.Sprintf("%s/annotated_test.go", tmpDir)
+
+	err := os.WriteFile(file, []byte(`package tests_test
+
+import "testing"
+
+// TestPayment checks the payment flow.
+// gotest-ls:tags slow,db owner=payments
+func TestPayment(t *testing.T) {
+	// gotest-ls:tags flaky
+	t.Run("refund", func(t *testing.T) {})
+
+	t.Run("charge", func(t *testing.T) {})
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+	}{
+		// The first case.
+		// gotest-ls:tags smoke team=core
+		{name: "first"},
+		{name: "second"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+}
+
+// TestPlain has no tags.
+func TestPlain(t *testing.T) {}
+`), os.ModePerm)
+	require.NoError(t, err)
+
+	got, err := pkg.List([]string{file})
+	require.NoError(t, err)
+
+	type annotated struct {
+		name   string
+		doc    string
+		tags   []string
+		labels map[string]string
+	}
+
+	var actual []annotated
+	for _, test := range got {
+		actual = append(actual, annotated{name: test.Name, doc: test.Doc, tags: test.Tags, labels: test.Labels})
+	}
+
+	require.Equal(t, []annotated{
+		{name: "TestPayment/charge", tags: []string{"db", "slow"}, labels: map[string]string{"owner": "payments"}},
+		{name: "TestPayment/refund", tags: []string{"db", "flaky", "slow"}, labels: map[string]string{"owner": "payments"}},
+		{name: "TestPlain", doc: "TestPlain has no tags."},
+		{name: "TestTable/first", doc: "The first case.", tags: []string{"smoke"}, labels: map[string]string{"team": "core"}},
+		{name: "TestTable/second"},
+	}, actual)
+
+	filtered := pkg.FilterByTags(got, []string{"slow", "owner=payments"})
+	require.Len(t, filtered, 2)
+
+	require.Empty(t, pkg.FilterByTags(got, []string{"owner=billing"}))
+	require.Equal(t, got, pkg.FilterByTags(got, nil))
+}
@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"context"
+	"go/token"
+)
+
+// At returns the innermost test enclosing the given position of the go test file at the given path, found with
+// the given options: the table test case whose literal contains the position, the subtest whose `t.Run` call
+// contains it or the top level test whose declaration contains it. A top level test with subtests is returned
+// with the patterns running all its subtests.
+// The line and the column are 1-based, the column is counted in bytes. When the column is 0, the test must
+// contain the whole line, from its first to its last line.
+// It returns nil if no test encloses the position, if the position is outside the file or if the test is
+// filtered out by the options.
+func At(ctx context.Context, opts Options, path string, line, column int) (*TestDetail, error) {
+	opts.Patterns, opts.Workspace = []string{path}, ""
+
+	filter, err := opts.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	fsys, err := newFileSystem(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := loadFiles(ctx, fsys, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) != 1 {
+		return nil, nil
+	}
+
+	set := token.NewFileSet()
+
+	nodes, _, err := listFileNodes(set, fsys, files[0], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *token.File
+
+	set.Iterate(func(f *token.File) bool {
+		file = f
+
+		return false
+	})
+
+	if file == nil || line < 1 || line > file.LineCount() || column < 0 {
+		return nil, nil
+	}
+
+	var innermost *testNode
+
+	for i, node := range nodes {
+		if !encloses(file, node, line, column) {
+			continue
+		}
+
+		if innermost == nil || node.node.End()-node.node.Pos() < innermost.node.End()-innermost.node.Pos() {
+			innermost = &nodes[i]
+		}
+	}
+
+	if innermost == nil {
+		return nil, nil
+	}
+
+	tests := filter.apply([]TestDetail{innermost.detail})
+	if len(tests) == 0 {
+		return nil, nil
+	}
+
+	return &tests[0], nil
+}
+
+// encloses checks if the source of the given test contains the given line and column of the file.
+func encloses(file *token.File, node testNode, line, column int) bool {
+	if column == 0 {
+		return file.Line(node.node.Pos()) <= line && line <= file.Line(node.node.End())
+	}
+
+	start := file.LineStart(line)
+
+	end := token.Pos(file.Base() + file.Size())
+	if line < file.LineCount() {
+		end = file.LineStart(line + 1)
+	}
+
+	pos := start + token.Pos(column-1)
+	if pos >= end {
+		return false
+	}
+
+	return node.node.Pos() <= pos && pos < node.node.End()
+}
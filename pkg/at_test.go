@@ -0,0 +1,95 @@
+package pkg_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_At(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"go.mod": {Data: []byte("module github.com/acme/svc\n")},
+		"app/app_test.go": {Data: []byte(`package app
+
+import "testing"
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+	}{
+		{name: "one"},
+		{
+			name: "two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+}
+
+func TestSub(t *testing.T) {
+	t.Run("first", func(t *testing.T) {
+		t.Log("first")
+	})
+}
+
+func TestPlain(t *testing.T) {}
+`)},
+		"app/app.go": {Data: []byte("package app\n")},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		line    int
+		column  int
+		want    string
+		pattern string
+	}{
+		{name: "table case", path: "app/app_test.go", line: 11, want: "TestSum/one", pattern: "^TestSum$/^one$"},
+		{name: "table case body", path: "app/app_test.go", line: 13, want: "TestSum/two", pattern: "^TestSum$/^two$"},
+		{name: "table case brace", path: "app/app_test.go", line: 12, column: 3, want: "TestSum/two"},
+		{name: "before table case", path: "app/app_test.go", line: 12, column: 1, want: "TestSum"},
+		{name: "table test loop", path: "app/app_test.go", line: 18, want: "TestSum", pattern: "^TestSum$"},
+		{name: "func keyword", path: "app/app_test.go", line: 5, column: 1, want: "TestSum"},
+		{name: "subtest body", path: "app/app_test.go", line: 24, want: "TestSub/first", pattern: "^TestSub$/^first$"},
+		{name: "subtest call", path: "app/app_test.go", line: 23, column: 2, want: "TestSub/first"},
+		{name: "plain test", path: "app/app_test.go", line: 28, want: "TestPlain", pattern: "^TestPlain$"},
+		{name: "between tests", path: "app/app_test.go", line: 21},
+		{name: "past the line", path: "app/app_test.go", line: 28, column: 40},
+		{name: "past the file", path: "app/app_test.go", line: 100},
+		{name: "not a test file", path: "app/app.go", line: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pkg.At(context.Background(), pkg.Options{FS: fsys}, tt.path, tt.line, tt.column)
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				require.Nil(t, got)
+
+				return
+			}
+
+			require.NotNil(t, got)
+			require.Equal(t, tt.want, got.Name)
+			require.Equal(t, "github.com/acme/svc/app", got.Package)
+
+			if tt.pattern != "" {
+				require.Equal(t, tt.pattern, got.RunPattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// modulePath is the path of the go module of the tool, its version is part of the cache keys.
+	modulePath = "github.com/ninadingole/gotest-ls"
+
+	// cacheVersion is the version of the format of the cache entries, it must be changed when the results of
+	// listing a file change, so the entries of the previous versions are not used.
+	cacheVersion = "2"
+
+	// cacheTrimFile is the file in the cache directory which contains the time of the last trim of the cache.
+	cacheTrimFile = "trim.txt"
+
+	// cacheTrimInterval is the minimum interval between two trims of the cache.
+	cacheTrimInterval = 24 * time.Hour
+
+	// cacheMaxAge is the duration after which an unused cache entry is removed by the trim.
+	cacheMaxAge = 7 * 24 * time.Hour
+)
+
+// DefaultCacheDir returns the default directory of the cache of the listed files, `gotest-ls` in the user cache
+// directory returned by os.UserCacheDir.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gotest-ls"), nil
+}
+
+// cache stores the result of listing each file on the disk, so the unchanged files are not parsed again.
+//
+// An entry is keyed by the path of the file, its size, its modification time, the hash of its contents, the
+// details reported with its tests, the options affecting the listing and the version of the tool. Each entry
+// is stored in its own file named after the hash of the path of the file and the hash of the key, so the
+// stale entries of a file are removed when a new entry is stored.
+// The cache is best effort, the errors reading or writing the entries are ignored.
+type cache struct {
+	dir     string
+	version string
+}
+
+// newCache returns the cache configured by the CacheDir of the given options.
+// It returns nil if the cache is disabled or if custom recognizers or changes are used, as they can't be part of
+// the keys.
+func newCache(opts Options) *cache {
+	if opts.CacheDir == "" || len(opts.Recognizers) > 0 || opts.Changes != nil {
+		return nil
+	}
+
+	return &cache{dir: opts.CacheDir, version: toolVersion()}
+}
+
+// toolVersion returns the version of the tool used in the cache keys.
+func toolVersion() string {
+	version := cacheVersion
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath {
+			version += " " + info.Main.Version
+		}
+
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version += " " + dep.Version
+			}
+		}
+	}
+
+	return version
+}
+
+// entry returns the path of the cache entry of the given file read from the given file system.
+// It returns false if the file can't be read.
+func (c *cache) entry(fsys *fileSystem, file testFile, opts Options) (string, bool) {
+	stat, err := fsys.stat(file.path)
+	if err != nil {
+		return "", false
+	}
+
+	content, err := fsys.readFile(file.path)
+	if err != nil {
+		return "", false
+	}
+
+	contentHash := sha256.Sum256(content)
+
+	key := hashStrings(
+		c.version,
+		file.absPath,
+		file.relPath,
+		file.pkg,
+		file.module.path,
+		file.module.root,
+		strconv.FormatInt(stat.Size(), 10),
+		stat.ModTime().UTC().Format(time.RFC3339Nano),
+		hex.EncodeToString(contentHash[:]),
+		strconv.FormatBool(opts.Tolerant),
+		strconv.FormatBool(opts.SkipGenerated),
+		strconv.FormatBool(opts.listsFuzz()),
+	)
+
+	return filepath.Join(c.dir, hashStrings(file.absPath)[:32]+"-"+key[:32]+".json"), true
+}
+
+// get returns the result stored in the given cache entry. The entry is marked as used so it is not trimmed.
+func (c *cache) get(entry string) (Result, bool) {
+	data, err := os.ReadFile(entry)
+	if err != nil {
+		return Result{}, false
+	}
+
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Result{}, false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(entry, now, now)
+
+	return result, true
+}
+
+// put stores the result in the given cache entry and removes the stale entries of the same file.
+func (c *cache) put(entry string, result Result) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), entry); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return
+	}
+
+	name := filepath.Base(entry)
+
+	stale, _ := filepath.Glob(filepath.Join(c.dir, name[:strings.Index(name, "-")+1]+"*.json"))
+	for _, path := range stale {
+		if path != entry {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// trim removes the entries which were not used for cacheMaxAge, like the entries of the deleted files.
+// The cache is trimmed at most once per cacheTrimInterval.
+func (c *cache) trim() {
+	now := time.Now()
+	trimFile := filepath.Join(c.dir, cacheTrimFile)
+
+	if data, err := os.ReadFile(trimFile); err == nil {
+		if last, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil &&
+			now.Sub(time.Unix(last, 0)) < cacheTrimInterval {
+			return
+		}
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || (filepath.Ext(entry.Name()) != ".json" && filepath.Ext(entry.Name()) != ".tmp") {
+			continue
+		}
+
+		if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) > cacheMaxAge {
+			_ = os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+
+	_ = os.WriteFile(trimFile, []byte(strconv.FormatInt(now.Unix(), 10)+"\n"), 0o644) //nolint: gosec
+}
+
+// listCachedFileTests lists all the tests in the given go test file like listFileTests, the result is read from
+// the cache if the file did not change since it was cached. A nil cache disables the cache.
+// The results of the files which can't be listed are not cached.
+func listCachedFileTests(set *token.FileSet, fsys *fileSystem, c *cache, file testFile, opts Options) (Result, error) {
+	if c == nil {
+		return listFileTests(set, fsys, file, opts)
+	}
+
+	entry, ok := c.entry(fsys, file, opts)
+	if !ok {
+		return listFileTests(set, fsys, file, opts)
+	}
+
+	if result, ok := c.get(entry); ok {
+		return result, nil
+	}
+
+	result, err := listFileTests(set, fsys, file, opts)
+	if err != nil {
+		return result, err
+	}
+
+	c.put(entry, result)
+
+	return result, nil
+}
+
+// hashStrings returns the hex encoded sha256 hash of the given strings.
+func hashStrings(values ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(values, "\x00")))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,83 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListWithOptionsCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tmpDir   = t.TempDir()
+		cacheDir = t.TempDir()
+		path     = filepath.Join(tmpDir, "app", "app_test.go")
+		opts     = pkg.Options{Patterns: []string{tmpDir}, CacheDir: cacheDir}
+	)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n"),
+		os.ModePerm))
+
+	names := func(t *testing.T) []string {
+		t.Helper()
+
+		got, err := pkg.ListWithOptions(context.Background(), opts)
+		require.NoError(t, err)
+
+		var names []string
+		for _, test := range got.Tests {
+			names = append(names, test.Name)
+		}
+
+		return names
+	}
+
+	entries := func(t *testing.T) []string {
+		t.Helper()
+
+		entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+		require.NoError(t, err)
+
+		return entries
+	}
+
+	require.Equal(t, []string{"TestApp"}, names(t))
+	require.Len(t, entries(t), 1)
+
+	// the cached result is used while the file does not change.
+	entry := entries(t)[0]
+	data, err := os.ReadFile(entry)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(entry, []byte(strings.ReplaceAll(string(data), "TestApp", "TestCached")),
+		os.ModePerm))
+	require.Equal(t, []string{"TestCached"}, names(t))
+
+	// the file is parsed again once it changes and the stale entry is removed.
+	require.NoError(t, os.WriteFile(path, []byte("package app\n\nimport \"testing\"\n\nfunc TestChanged(t *testing.T) {}\n"),
+		os.ModePerm))
+	require.Equal(t, []string{"TestChanged"}, names(t))
+	require.Len(t, entries(t), 1)
+	require.NotEqual(t, entry, entries(t)[0])
+
+	// the fuzz targets listed with the fuzz kind are not read from the entry listed without them.
+	require.NoError(t, os.WriteFile(path, []byte("package app\n\nimport \"testing\"\n\n"+
+		"func TestChanged(t *testing.T) {}\n\nfunc FuzzChanged(f *testing.F) {}\n"), os.ModePerm))
+	require.Equal(t, []string{"TestChanged"}, names(t))
+
+	opts.Kinds = []pkg.Kind{pkg.KindFuzz}
+	require.Equal(t, []string{"FuzzChanged"}, names(t))
+
+	opts.Kinds = nil
+
+	// the cache is disabled with custom recognizers.
+	opts.Recognizers = []pkg.Recognizer{pkg.IsGoTest}
+	require.NoError(t, os.WriteFile(entries(t)[0], []byte(`{"tests":[{"name":"TestCached"}]}`), os.ModePerm))
+	require.Equal(t, []string{"TestChanged"}, names(t))
+}
@@ -0,0 +1,212 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Changes maps the absolute paths of the changed files to the ranges of their changed lines, see GitChanges.
+// A file without ranges changed as a whole, like a file which is not tracked yet.
+type Changes map[string][]LineRange
+
+// LineRange is a range of lines of a file, from Start to End included, the first line is 1.
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// hunkHeader matches the header of a hunk of a unified diff, with the first line and the number of lines of the
+// hunk in the new file.
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// GitChanges returns the changes of the files of the git repository containing the given directory since the
+// given ref, HEAD if empty, including the staged and unstaged changes and the untracked files. The changes are
+// read from the local repository with `git diff`, the deleted files are ignored and the lines around deleted
+// lines are reported as changed. It returns a *GitError if git fails, for example if the ref is unknown.
+func GitChanges(ctx context.Context, dir, ref string) (Changes, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, &GitError{Dir: dir, Ref: ref, Err: err}
+	}
+
+	cdup, err := git(ctx, absDir, ref, "rev-parse", "--show-cdup")
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Join(absDir, strings.TrimSpace(string(cdup)))
+
+	diff, err := git(ctx, root, ref, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff",
+		"--no-renames", "--unified=0", "--src-prefix=a/", "--dst-prefix=b/", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := parseDiff(diff, root)
+	if err != nil {
+		return nil, &GitError{Dir: dir, Ref: ref, Err: err}
+	}
+
+	untracked, err := git(ctx, root, ref, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range strings.Split(string(untracked), "\x00") {
+		if path != "" {
+			changes[filepath.Join(root, filepath.FromSlash(path))] = nil
+		}
+	}
+
+	return changes, nil
+}
+
+// git runs git with the given arguments in the given directory and returns its output.
+// It returns the error of the context if it is done, or a *GitError.
+func git(ctx context.Context, dir, ref string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, &GitError{Dir: dir, Ref: ref, Err: fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))}
+	}
+
+	return output, nil
+}
+
+// parseDiff returns the changed lines of the files of a unified diff without context lines, the paths of the
+// files are relative to the given root.
+func parseDiff(diff []byte, root string) (Changes, error) {
+	var (
+		changes = make(Changes)
+		current string
+		header  bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(nil, len(diff)+1)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			current, header = "", true
+		case header && strings.HasPrefix(line, "+++ "):
+			current = ""
+
+			path := strings.TrimPrefix(line, "+++ ")
+			if unquoted, err := strconv.Unquote(path); err == nil {
+				path = unquoted
+			}
+
+			if strings.HasPrefix(path, "b/") {
+				current = filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(path, "b/")))
+			}
+		case strings.HasPrefix(line, "@@"):
+			header = false
+
+			match := hunkHeader.FindStringSubmatch(line)
+			if match == nil || current == "" {
+				continue
+			}
+
+			start, _ := strconv.Atoi(match[1])
+
+			count := 1
+			if match[2] != "" {
+				count, _ = strconv.Atoi(match[2])
+			}
+
+			// the lines deleted after the start line changed the lines around them.
+			if count == 0 {
+				changes[current] = append(changes[current], LineRange{Start: start, End: start + 1})
+
+				continue
+			}
+
+			changes[current] = append(changes[current], LineRange{Start: start, End: start + count - 1})
+		}
+	}
+
+	return changes, scanner.Err()
+}
+
+// changedNodes returns the tests of a file changed on the given lines: the tests whose source contains a changed
+// line and all the subtests of a top level test changed outside of its subtests. All the tests are returned if
+// a line outside of the tests changed, like the line of a helper or an import.
+func changedNodes(set *token.FileSet, nodes []testNode, ranges []LineRange) []testNode {
+	type lines struct{ start, end int }
+
+	var (
+		selected = make([]bool, len(nodes))
+		spans    = make([]lines, len(nodes))
+	)
+
+	for i, node := range nodes {
+		spans[i] = lines{start: set.Position(node.node.Pos()).Line, end: set.Position(node.node.End()).Line}
+	}
+
+	for _, r := range ranges {
+		for line := r.Start; line <= r.End; line++ {
+			var tests, parents []int
+
+			for i, node := range nodes {
+				if spans[i].start <= line && line <= spans[i].end {
+					if node.parent {
+						parents = append(parents, i)
+					} else {
+						tests = append(tests, i)
+					}
+				}
+			}
+
+			switch {
+			case len(tests) > 0:
+				for _, i := range tests {
+					selected[i] = true
+				}
+			case len(parents) > 0:
+				for _, p := range parents {
+					for i, node := range nodes {
+						if strings.HasPrefix(node.detail.Name, nodes[p].detail.Name+"/") {
+							selected[i] = true
+						}
+					}
+				}
+			default:
+				return nodes
+			}
+		}
+	}
+
+	var changed []testNode
+
+	for i, node := range nodes {
+		if selected[i] {
+			changed = append(changed, node)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,127 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+const changedTestFile = `package app
+
+import "testing"
+
+func helper() int { return 1 }
+
+func TestPlain(t *testing.T) {
+	_ = helper()
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+	}{
+		{name: "one"},
+		{name: "two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+}
+
+func TestSub(t *testing.T) {
+	t.Run("first", func(t *testing.T) {
+		t.Log("first")
+	})
+
+	t.Run("second", func(t *testing.T) {
+		t.Log("second")
+	})
+}
+`
+
+func Test_GitChanges(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil || testing.Short() {
+		t.Skip("runs git")
+	}
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "app", "app_test.go")
+
+	replace := func(old, new string) {
+		content, err := os.ReadFile(testFile)
+		require.NoError(t, err)
+		require.Contains(t, string(content), old)
+		require.NoError(t, os.WriteFile(testFile, []byte(strings.Replace(string(content), old, new, 1)), os.ModePerm))
+	}
+
+	runGit := func(args ...string) {
+		cmd := exec.Command("git", append([]string{
+			"-c", "user.name=gotest-ls", "-c", "user.email=gotest-ls@example.com", "-c", "commit.gpgsign=false",
+		}, args...)...)
+		cmd.Dir = tmpDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	changedTests := func(ref string) []string {
+		changes, err := pkg.GitChanges(context.Background(), filepath.Join(tmpDir, "app"), ref)
+		require.NoError(t, err)
+
+		got, err := pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{tmpDir}, Changes: changes})
+		require.NoError(t, err)
+
+		return testNames(got.Tests)
+	}
+
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":              "module example.com/changes\n",
+		"app/app_test.go":     changedTestFile,
+		"other/other_test.go": "package other\n\nimport \"testing\"\n\nfunc TestOther(t *testing.T) {}\n",
+	})
+
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "base")
+	runGit("tag", "base")
+
+	require.Empty(t, changedTests(""))
+
+	replace("_ = helper()", "_ = helper() + 1")
+	runGit("commit", "-q", "-a", "-m", "change")
+
+	replace(`{name: "two"},`, `{name: "two"}, // staged`)
+	runGit("add", ".")
+
+	replace(`t.Log("second")`, `t.Log("unstaged")`)
+	writeFiles(t, tmpDir, map[string]string{
+		"extra/extra_test.go": "package extra\n\nimport \"testing\"\n\nfunc TestExtra(t *testing.T) {}\n",
+	})
+
+	require.Equal(t, []string{"TestExtra", "TestPlain", "TestSub/second", "TestTable/two"}, changedTests("base"))
+	require.Equal(t, []string{"TestExtra", "TestSub/second", "TestTable/two"}, changedTests(""))
+
+	replace("t.Run(tt.name, func(t *testing.T) {})", "t.Run(tt.name, func(t *testing.T) { t.Parallel() })")
+	require.Equal(t, []string{"TestExtra", "TestSub/second", "TestTable/one", "TestTable/two"}, changedTests(""))
+
+	replace("func helper() int { return 1 }", "func helper() int { return 2 }")
+	require.Equal(t, []string{
+		"TestExtra", "TestPlain", "TestSub/first", "TestSub/second", "TestTable/one", "TestTable/two",
+	}, changedTests(""))
+
+	_, err := pkg.GitChanges(context.Background(), tmpDir, "unknown")
+
+	var gitErr *pkg.GitError
+	require.ErrorAs(t, err, &gitErr)
+	require.Equal(t, "unknown", gitErr.Ref)
+}
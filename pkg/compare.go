@@ -0,0 +1,230 @@
+package pkg
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Comparison is the difference between the tests discovered statically and the tests run by `go test`,
+// reported by Compare.
+type Comparison struct {
+	// NotRun contains the discovered tests which did not run, they may be dead or excluded by build tags.
+	NotRun []TestDetail `json:"notRun"`
+	// NotDiscovered contains the tests which ran but were not discovered, like the subtests with dynamic names.
+	NotDiscovered []UndiscoveredTest `json:"notDiscovered"`
+	// Renamed contains the discovered subtests which ran under a different name.
+	Renamed []RenamedTest `json:"renamed"`
+}
+
+// UndiscoveredTest is a test which ran but was not discovered. The file and the line are the ones of the closest
+// discovered test, its closest discovered parent or the first discovered subtest of its closest parent, they are
+// empty if no test of its top level test was discovered.
+type UndiscoveredTest struct {
+	Name         string `json:"name"`
+	Package      string `json:"package"`
+	RelativePath string `json:"relativePath,omitempty"`
+	AbsolutePath string `json:"absolutePath,omitempty"`
+	Line         int    `json:"line,omitempty"`
+}
+
+// RenamedTest is a discovered subtest which ran under a different name, because `go test` rewrote its name
+// differently, for example to make the names of the subtests unique.
+type RenamedTest struct {
+	Name string     `json:"name"`
+	Test TestDetail `json:"test"`
+}
+
+// uniqueSuffix matches the suffix added by `go test` to make the names of the subtests unique.
+var uniqueSuffix = regexp.MustCompile(`#\d+$`)
+
+// Compare reads the events written by `go test -json` from r and compares the tests which ran with the given
+// discovered tests. Only the packages which ran are compared, and the benchmarks are ignored as `go test` only
+// runs them with `-bench`.
+//
+// A discovered subtest which did not run is reported as renamed instead if a subtest of the same parent, which
+// was not discovered, ran with the same letters and digits in its name, ignoring the `#01` suffixes. The
+// results are sorted by package and name. The tests which are not part of a module are matched with the local
+// import path `go test` reports for their directory in GOPATH mode.
+func Compare(tests []TestDetail, r io.Reader) (*Comparison, error) {
+	var (
+		ran      = make(map[string]bool)
+		ranNames []comparedTest
+		packages = make(map[string]bool)
+		dirs     = make(map[string]string)
+	)
+
+	for _, test := range tests {
+		if test.Package == "" {
+			dirs[localImportPath(test.target())] = test.target()
+		}
+	}
+
+	err := ReadTestEvents(r, func(event TestEvent) error {
+		if event.Package == "" {
+			return nil
+		}
+
+		if dir, ok := dirs[event.Package]; ok {
+			event.Package = dir
+		}
+
+		packages[event.Package] = true
+
+		if event.Test == "" || event.Action == ActionOutput {
+			return nil
+		}
+
+		test := comparedTest{pkg: event.Package, name: event.Test}
+		if !ran[test.key()] {
+			ran[test.key()] = true
+			ranNames = append(ranNames, test)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		discovered = make(map[string]TestDetail)
+		parents    = make(map[string]TestDetail)
+		comparison = &Comparison{NotRun: []TestDetail{}, NotDiscovered: []UndiscoveredTest{}, Renamed: []RenamedTest{}}
+	)
+
+	sorted := append([]TestDetail(nil), tests...)
+	sortTests(sorted)
+
+	for _, test := range sorted {
+		if !packages[test.target()] || test.Kind() == KindBenchmark {
+			continue
+		}
+
+		discovered[comparedTest{pkg: test.target(), name: test.Name}.key()] = test
+
+		// the parents are located at their first subtest.
+		for name := parentName(test.Name); name != ""; name = parentName(name) {
+			key := comparedTest{pkg: test.target(), name: name}.key()
+			if previous, ok := parents[key]; !ok || test.AbsolutePath < previous.AbsolutePath ||
+				(test.AbsolutePath == previous.AbsolutePath && test.Line < previous.Line) {
+				parents[key] = test
+			}
+		}
+
+		if !ran[comparedTest{pkg: test.target(), name: test.Name}.key()] {
+			comparison.NotRun = append(comparison.NotRun, test)
+		}
+	}
+
+	for _, test := range ranNames {
+		if _, ok := discovered[test.key()]; ok {
+			continue
+		}
+
+		if _, ok := parents[test.key()]; ok {
+			continue
+		}
+
+		if i := findRenamed(comparison.NotRun, test); i >= 0 {
+			comparison.Renamed = append(comparison.Renamed, RenamedTest{Name: test.name, Test: comparison.NotRun[i]})
+			comparison.NotRun = append(comparison.NotRun[:i], comparison.NotRun[i+1:]...)
+
+			continue
+		}
+
+		undiscovered := UndiscoveredTest{Name: test.name, Package: test.pkg}
+
+		for name := parentName(test.name); name != ""; name = parentName(name) {
+			closest, ok := discovered[comparedTest{pkg: test.pkg, name: name}.key()]
+			if !ok {
+				closest, ok = parents[comparedTest{pkg: test.pkg, name: name}.key()]
+			}
+
+			if ok {
+				undiscovered.RelativePath, undiscovered.AbsolutePath = closest.RelativePath, closest.AbsolutePath
+				undiscovered.Line = closest.Line
+
+				break
+			}
+		}
+
+		comparison.NotDiscovered = append(comparison.NotDiscovered, undiscovered)
+	}
+
+	sort.SliceStable(comparison.NotDiscovered, func(i, j int) bool {
+		a, b := comparison.NotDiscovered[i], comparison.NotDiscovered[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+
+		return a.Name < b.Name
+	})
+
+	sort.SliceStable(comparison.Renamed, func(i, j int) bool {
+		a, b := comparison.Renamed[i], comparison.Renamed[j]
+		if a.Test.target() != b.Test.target() {
+			return a.Test.target() < b.Test.target()
+		}
+
+		return a.Name < b.Name
+	})
+
+	return comparison, nil
+}
+
+// comparedTest is a test which ran, identified by its package and its name.
+type comparedTest struct {
+	pkg  string
+	name string
+}
+
+// key returns the key identifying the test.
+func (t comparedTest) key() string {
+	return t.pkg + "\x00" + t.name
+}
+
+// findRenamed returns the index of the discovered test which did not run and was renamed to the given test
+// which ran, -1 if there is none.
+func findRenamed(notRun []TestDetail, test comparedTest) int {
+	parent := parentName(test.name)
+	if parent == "" {
+		return -1
+	}
+
+	normalized := normalizeName(test.name)
+
+	for i, candidate := range notRun {
+		if candidate.target() == test.pkg && parentName(candidate.Name) == parent &&
+			normalizeName(candidate.Name) == normalized {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parentName returns the name of the parent of the test with the given name, empty for a top level test.
+func parentName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i]
+	}
+
+	return ""
+}
+
+// normalizeName returns the letters and digits of the last level of the name, without the suffix which makes
+// the names of the subtests unique.
+func normalizeName(name string) string {
+	name = uniqueSuffix.ReplaceAllString(name[strings.LastIndex(name, "/")+1:], "")
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, name)
+}
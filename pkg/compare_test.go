@@ -0,0 +1,67 @@
+package pkg_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Compare(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tests = []pkg.TestDetail{
+			{Name: "TestA", Package: "example.com/a", RelativePath: "a_test.go", Line: 5},
+			{Name: "TestB/static", Package: "example.com/a", RelativePath: "a_test.go", Line: 12},
+			{Name: "TestB/x_=_1", Package: "example.com/a", RelativePath: "a_test.go", Line: 14},
+			{Name: "TestB/first", Package: "example.com/a", RelativePath: "a_test.go", Line: 10},
+			{Name: "TestDead", Package: "example.com/a", RelativePath: "a_test.go", Line: 20},
+			{Name: "BenchmarkA", Package: "example.com/a", RelativePath: "a_test.go", Line: 25},
+			{Name: "TestOther", Package: "example.com/other", RelativePath: "other_test.go", Line: 3},
+		}
+		events = `{"Action":"run","Package":"example.com/a","Test":"TestA"}
+{"Action":"pass","Package":"example.com/a","Test":"TestA"}
+{"Action":"run","Package":"example.com/a","Test":"TestB"}
+{"Action":"run","Package":"example.com/a","Test":"TestB/first"}
+{"Action":"run","Package":"example.com/a","Test":"TestB/static"}
+{"Action":"run","Package":"example.com/a","Test":"TestB/x=1"}
+{"Action":"run","Package":"example.com/a","Test":"TestB/dynamic_42"}
+{"Action":"output","Package":"example.com/a","Test":"TestB/dynamic_42","Output":"ok\n"}
+{"Action":"run","Package":"example.com/a","Test":"TestGenerated"}
+{"Action":"pass","Package":"example.com/a","Elapsed":0.1}`
+	)
+
+	got, err := pkg.Compare(tests, strings.NewReader(events))
+	require.NoError(t, err)
+	require.Equal(t, &pkg.Comparison{
+		NotRun: []pkg.TestDetail{tests[4]},
+		NotDiscovered: []pkg.UndiscoveredTest{
+			{Name: "TestB/dynamic_42", Package: "example.com/a", RelativePath: "a_test.go", Line: 10},
+			{Name: "TestGenerated", Package: "example.com/a"},
+		},
+		Renamed: []pkg.RenamedTest{
+			{Name: "TestB/x=1", Test: tests[2]},
+		},
+	}, got)
+}
+
+func Test_CompareOutsideModule(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(string(filepath.Separator), "src", "app")
+	tests := []pkg.TestDetail{
+		{Name: "TestA", AbsolutePath: filepath.Join(dir, "app_test.go"), Line: 5},
+		{Name: "TestB", AbsolutePath: filepath.Join(dir, "app_test.go"), Line: 7},
+	}
+
+	// go test runs the packages outside of a module in GOPATH mode, with a local import path.
+	events := `{"Action":"pass","Package":"_/src/app","Test":"TestA","Elapsed":0.1}`
+
+	got, err := pkg.Compare(tests, strings.NewReader(events))
+	require.NoError(t, err)
+	require.Equal(t, []pkg.TestDetail{tests[1]}, got.NotRun)
+	require.Empty(t, got.NotDiscovered)
+}
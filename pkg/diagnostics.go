@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"sort"
+)
+
+// Diagnostic is a problem found while parsing a go test file, it is reported instead of failing the listing
+// when the Tolerant option is set.
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// Result contains the tests found and the diagnostics reported while listing the tests.
+type Result struct {
+	Tests       []TestDetail `json:"tests"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// parseTestFile parses the given go test file.
+// If the Tolerant option is set, the syntax errors are returned as diagnostics along with the partial ast
+// built by the parser, otherwise the syntax errors are returned as a *ParseError.
+// A *WalkError is returned if the file can't be read from the given file system.
+func parseTestFile(set *token.FileSet, fsys *fileSystem, path string, opts Options) (*ast.File, []Diagnostic, error) {
+	mode := parser.ParseComments
+	if opts.Tolerant {
+		mode |= parser.AllErrors
+	}
+
+	src, err := fsys.readFile(path)
+	if err != nil {
+		return nil, nil, &WalkError{Path: path, Err: err}
+	}
+
+	file, err := parser.ParseFile(set, path, src, mode)
+	if err == nil {
+		return file, nil, nil
+	}
+
+	var errorList scanner.ErrorList
+	if !errors.As(err, &errorList) {
+		return nil, nil, &WalkError{Path: path, Err: err}
+	}
+
+	if !opts.Tolerant {
+		return nil, nil, newParseError(path, err)
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(errorList))
+
+	for _, e := range errorList {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:    e.Pos.Filename,
+			Line:    e.Pos.Line,
+			Column:  e.Pos.Column,
+			Message: e.Msg,
+		})
+	}
+
+	return file, diagnostics, nil
+}
+
+// sortDiagnostics sorts the diagnostics by file, line and column.
+func sortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].File != diagnostics[j].File {
+			return diagnostics[i].File < diagnostics[j].File
+		}
+
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+}
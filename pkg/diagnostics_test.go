@@ -0,0 +1,46 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListTolerant(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "broken_test.go")
+
+	err := os.WriteFile(file, []byte(`package tests_test
+
+import "testing"
+
+func TestBefore(t *testing.T) {
+	t.Run("sub", func(t *testing.T) {})
+}
+
+dummy dummy test
+`), os.ModePerm)
+	require.NoError(t, err)
+
+	_, err = pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{file}})
+	require.Error(t, err)
+
+	got, err := pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{file}, Tolerant: true})
+	require.NoError(t, err)
+
+	var names []string
+	for _, test := range got.Tests {
+		names = append(names, test.Name)
+	}
+
+	require.Equal(t, []string{"TestBefore/sub"}, names)
+	require.Equal(t, []pkg.Diagnostic{
+		{File: file, Line: 9, Column: 1, Message: "expected declaration, found dummy"},
+	}, got.Diagnostics)
+}
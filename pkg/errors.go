@@ -0,0 +1,135 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ParseError is returned when a go test file or a `go.work` file can't be parsed.
+// Line and Column contain the position of the first syntax error, they are zero if the position is unknown.
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+// newParseError returns a ParseError for the given file with the position of the first syntax error in err.
+func newParseError(file string, err error) *ParseError {
+	parseErr := &ParseError{File: file, Err: err}
+
+	var (
+		errorList  scanner.ErrorList
+		modErrList modfile.ErrorList
+	)
+
+	switch {
+	case errors.As(err, &errorList) && len(errorList) > 0:
+		parseErr.Line, parseErr.Column = errorList[0].Pos.Line, errorList[0].Pos.Column
+	case errors.As(err, &modErrList) && len(modErrList) > 0:
+		parseErr.Line, parseErr.Column = modErrList[0].Pos.Line, modErrList[0].Pos.LineRune
+	}
+
+	return parseErr
+}
+
+// Error returns the error message.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse %s: %v", e.File, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// WalkError is returned when a directory can't be walked or a package pattern can't be resolved.
+// Path contains the directory, file or package patterns which failed.
+type WalkError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *WalkError) Error() string {
+	return fmt.Sprintf("walk %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *WalkError) Unwrap() error {
+	return e.Err
+}
+
+// PathError is returned when the absolute path or the relative path of a test file can't be computed.
+// Base contains the directory the relative path is computed from.
+type PathError struct {
+	Path string
+	Base string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *PathError) Error() string {
+	if e.Base == "" {
+		return fmt.Sprintf("resolve path %s: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("resolve path %s relative to %s: %v", e.Path, e.Base, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// PatternError is returned when an include or exclude glob pattern is invalid.
+type PatternError struct {
+	Pattern string
+	Err     error
+}
+
+// Error returns the error message.
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("invalid glob pattern %q: %v", e.Pattern, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// MatchError is returned when a `go test -run` or `-skip` pattern is invalid.
+type MatchError struct {
+	Pattern string
+	Err     error
+}
+
+// Error returns the error message.
+func (e *MatchError) Error() string {
+	return fmt.Sprintf("invalid run pattern %q: %v", e.Pattern, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *MatchError) Unwrap() error {
+	return e.Err
+}
+
+// GitError is returned when the changes of a git repository can't be read.
+type GitError struct {
+	Dir string
+	Ref string
+	Err error
+}
+
+// Error returns the error message.
+func (e *GitError) Error() string {
+	return fmt.Sprintf("cannot read the changes of %q since %q: %v", e.Dir, e.Ref, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
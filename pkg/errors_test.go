@@ -0,0 +1,82 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListErrors(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	brokenFile := filepath.Join(tmpDir, "broken_test.go")
+	require.NoError(t, os.WriteFile(brokenFile, []byte("package tests_test\n\ndummy dummy test\n"), os.ModePerm))
+
+	workFile := filepath.Join(tmpDir, "go.work")
+	require.NoError(t, os.WriteFile(workFile, []byte("go 1.19\n\nuse (\n"), os.ModePerm))
+
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := pkg.List([]string{brokenFile})
+
+		var parseErr *pkg.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, pkg.ParseError{File: brokenFile, Line: 3, Column: 1, Err: parseErr.Err}, *parseErr)
+	})
+
+	t.Run("walk error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := pkg.List([]string{filepath.Join(tmpDir, "missing")})
+
+		var walkErr *pkg.WalkError
+		require.ErrorAs(t, err, &walkErr)
+		require.Equal(t, filepath.Join(tmpDir, "missing"), walkErr.Path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("pattern error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{tmpDir}, Include: []string{"[a-"}})
+
+		var patternErr *pkg.PatternError
+		require.ErrorAs(t, err, &patternErr)
+		require.Equal(t, "[a-", patternErr.Pattern)
+	})
+
+	t.Run("match error", func(t *testing.T) {
+		t.Parallel()
+
+		opts := pkg.Options{Patterns: []string{tmpDir}, Skip: "Test/["}
+
+		_, listErr := pkg.ListWithOptions(context.Background(), opts)
+		funcErr := pkg.ListFunc(context.Background(), opts, func(pkg.Result) error { return nil })
+		watchErr := pkg.Watch(context.Background(), opts, time.Second, func(pkg.Event) error { return nil })
+		_, atErr := pkg.At(context.Background(), opts, tmpDir, 1, 0)
+
+		for _, err := range []error{listErr, funcErr, watchErr, atErr} {
+			var matchErr *pkg.MatchError
+			require.ErrorAs(t, err, &matchErr)
+			require.Equal(t, "Test/[", matchErr.Pattern)
+		}
+	})
+
+	t.Run("workspace parse error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := pkg.ListWithOptions(context.Background(), pkg.Options{Workspace: workFile})
+
+		var parseErr *pkg.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, workFile, parseErr.File)
+		require.Equal(t, 4, parseErr.Line)
+	})
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// Actions of the events written by `go test -json`.
+const (
+	ActionRun    = "run"
+	ActionPass   = "pass"
+	ActionFail   = "fail"
+	ActionSkip   = "skip"
+	ActionOutput = "output"
+	// ActionBuildOutput is the action of the output of the build of a package, reported since Go 1.24.
+	ActionBuildOutput = "build-output"
+)
+
+// TestEvent is an event written by `go test -json`, see `go doc test2json`.
+// Test is empty for the events of the package itself, Elapsed is the duration in seconds of the test or the
+// package for the pass and fail actions.
+type TestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// ReadTestEvents reads the events written by `go test -json` from r and calls fn with each event.
+// The lines which are not JSON objects, like the build errors printed by `go test`, are skipped.
+// It stops and returns the error when fn returns an error or r can't be read.
+func ReadTestEvents(r io.Reader, fn func(TestEvent) error) error {
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		var event TestEvent
+		if line = bytes.TrimSpace(line); len(line) > 0 && line[0] == '{' && json.Unmarshal(line, &event) == nil {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
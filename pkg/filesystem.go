@@ -0,0 +1,253 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileSystem reads the files and the directories the tests are listed from.
+// It reads from the disk, or from the fs.FS of the options, and the files of the overlay take precedence over them.
+//
+// The paths are always handled as filepath paths. When reading from an fs.FS, the root of the fs.FS is treated as
+// the root directory `/` and the relative paths are relative to it.
+type fileSystem struct {
+	fsys fs.FS
+	// overlay contains the contents of the overlay files keyed by their absolute path.
+	overlay map[string][]byte
+}
+
+// newFileSystem returns the fileSystem configured with the FS and the Overlay of the given options.
+// It returns a *PathError if the absolute path of an overlay file can't be computed.
+func newFileSystem(opts Options) (*fileSystem, error) {
+	fsys := &fileSystem{fsys: opts.FS, overlay: make(map[string][]byte, len(opts.Overlay))}
+
+	for path, content := range opts.Overlay {
+		absPath, err := fsys.abs(path)
+		if err != nil {
+			return nil, &PathError{Path: path, Err: err}
+		}
+
+		fsys.overlay[absPath] = content
+	}
+
+	return fsys, nil
+}
+
+// abs returns the absolute path of the given path.
+func (f *fileSystem) abs(path string) (string, error) {
+	if f.fsys == nil {
+		return filepath.Abs(path)
+	}
+
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+
+	return filepath.Join(string(filepath.Separator), path), nil
+}
+
+// fsPath returns the fs.FS path of the given path.
+func (f *fileSystem) fsPath(path string) string {
+	absPath, _ := f.abs(path)
+
+	if absPath = strings.TrimLeft(filepath.ToSlash(absPath), "/"); absPath == "" {
+		return "."
+	}
+
+	return absPath
+}
+
+// readFile returns the contents of the given file.
+func (f *fileSystem) readFile(path string) ([]byte, error) {
+	if absPath, err := f.abs(path); err == nil {
+		if content, ok := f.overlay[absPath]; ok {
+			return content, nil
+		}
+	}
+
+	if f.fsys == nil {
+		return os.ReadFile(path)
+	}
+
+	return fs.ReadFile(f.fsys, f.fsPath(path))
+}
+
+// openFile opens the given file for reading, it is used to match the build constraints of the files.
+func (f *fileSystem) openFile(path string) (io.ReadCloser, error) {
+	content, err := f.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// stat returns the fs.FileInfo of the given file or directory.
+func (f *fileSystem) stat(path string) (fs.FileInfo, error) {
+	if absPath, err := f.abs(path); err == nil {
+		if content, ok := f.overlay[absPath]; ok {
+			return overlayFile{name: filepath.Base(absPath), size: int64(len(content))}, nil
+		}
+	}
+
+	if f.fsys == nil {
+		return os.Stat(path)
+	}
+
+	return fs.Stat(f.fsys, f.fsPath(path))
+}
+
+// isFile checks if the given path is an existing file.
+func (f *fileSystem) isFile(path string) bool {
+	stat, err := f.stat(path)
+
+	return err == nil && !stat.IsDir()
+}
+
+// walkDir walks the file tree rooted at root like filepath.WalkDir.
+// The overlay files in the tree which do not exist on the disk or in the fs.FS are visited after the other files,
+// unless they are in a directory skipped by fn.
+func (f *fileSystem) walkDir(root string, fn fs.WalkDirFunc) error {
+	absRoot, err := f.abs(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	if content, ok := f.overlay[absRoot]; ok {
+		return fn(root, overlayFile{name: filepath.Base(absRoot), size: int64(len(content))}, nil)
+	}
+
+	var (
+		visited = make(map[string]bool)
+		skipped []string
+	)
+
+	visit := func(path string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return fn(path, d, err)
+		}
+
+		visited[filepath.Join(absRoot, rel)] = true
+
+		err = fn(path, d, err)
+		if errors.Is(err, filepath.SkipDir) && d != nil && d.IsDir() {
+			skipped = append(skipped, filepath.Join(absRoot, rel))
+		}
+
+		return err
+	}
+
+	if f.fsys == nil {
+		err = filepath.WalkDir(root, visit)
+	} else {
+		fsRoot := f.fsPath(root)
+		err = fs.WalkDir(f.fsys, fsRoot, func(path string, d fs.DirEntry, err error) error {
+			if fsRoot != "." {
+				path = strings.TrimPrefix(strings.TrimPrefix(path, fsRoot), "/")
+			}
+
+			return visit(filepath.Join(root, filepath.FromSlash(path)), d, err)
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return f.walkOverlay(root, absRoot, visited, skipped, fn)
+}
+
+// walkOverlay visits the overlay files under the given root which were not visited and are not in a skipped
+// directory, in lexical order.
+func (f *fileSystem) walkOverlay(root, absRoot string, visited map[string]bool, skipped []string,
+	fn fs.WalkDirFunc,
+) error {
+	var paths []string
+
+	for absPath := range f.overlay {
+		if !visited[absPath] && isWithin(absRoot, absPath) && !isWithinAny(skipped, absPath) {
+			paths = append(paths, absPath)
+		}
+	}
+
+	sort.Strings(paths)
+
+	for _, absPath := range paths {
+		rel, err := filepath.Rel(absRoot, absPath)
+		if err != nil {
+			return err
+		}
+
+		entry := overlayFile{name: filepath.Base(absPath), size: int64(len(f.overlay[absPath]))}
+		if err := fn(filepath.Join(root, rel), entry, nil); err != nil && !errors.Is(err, filepath.SkipDir) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isWithin checks if the given path is inside the given directory.
+func isWithin(dir, path string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// isWithinAny checks if the given path is inside one of the given directories.
+func isWithinAny(dirs []string, path string) bool {
+	for _, dir := range dirs {
+		if isWithin(dir, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// overlayFile is the fs.DirEntry and the fs.FileInfo of a file of the overlay.
+type overlayFile struct {
+	name string
+	size int64
+}
+
+func (o overlayFile) Name() string               { return o.name }
+func (o overlayFile) Size() int64                { return o.size }
+func (o overlayFile) Mode() fs.FileMode          { return 0o644 }
+func (o overlayFile) ModTime() time.Time         { return time.Time{} }
+func (o overlayFile) IsDir() bool                { return false }
+func (o overlayFile) Sys() interface{}           { return nil }
+func (o overlayFile) Type() fs.FileMode          { return 0 }
+func (o overlayFile) Info() (fs.FileInfo, error) { return o, nil }
+
+// withOverlayTestFiles returns the given names of the test files in the directory along with the names of the test
+// files of the overlay in the directory, sorted.
+func (f *fileSystem) withOverlayTestFiles(dir string, names []string) []string {
+	absDir, err := f.abs(dir)
+	if err != nil {
+		return names
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+
+	for absPath := range f.overlay {
+		if name := filepath.Base(absPath); filepath.Dir(absPath) == absDir && !seen[name] &&
+			strings.HasSuffix(name, "_test.go") {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
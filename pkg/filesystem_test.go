@@ -0,0 +1,148 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListWithOptionsFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"go.mod":                    {Data: []byte("module github.com/acme/svc\n")},
+		".gitignore":                {Data: []byte("build/\n")},
+		"app/app_test.go":           {Data: []byte("package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n")},
+		"app/nested/nested_test.go": {Data: []byte("package nested\n\nimport \"testing\"\n\nfunc TestNested(t *testing.T) {}\n")},
+		"build/build_test.go":       {Data: []byte("package build\n\nimport \"testing\"\n\nfunc TestBuild(t *testing.T) {}\n")},
+		"tools/go.mod":              {Data: []byte("module github.com/acme/tools\n")},
+		"tools/tools_test.go":       {Data: []byte("package tools\n\nimport \"testing\"\n\nfunc TestTools(t *testing.T) {}\n")},
+	}
+
+	tests := []struct {
+		name    string
+		opts    pkg.Options
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "directory",
+			opts: pkg.Options{Patterns: []string{"app"}},
+			want: []string{"TestApp", "TestNested"},
+		},
+		{
+			name: "package pattern",
+			opts: pkg.Options{Patterns: []string{"./..."}},
+			want: []string{"TestApp", "TestNested"},
+		},
+		{
+			name: "file",
+			opts: pkg.Options{Patterns: []string{"tools/tools_test.go"}},
+			want: []string{"TestTools"},
+		},
+		{
+			name: "overlay takes precedence",
+			opts: pkg.Options{
+				Patterns: []string{"app"},
+				Overlay: map[string][]byte{
+					"app/app_test.go":   []byte("package app\n\nimport \"testing\"\n\nfunc TestEdited(t *testing.T) {}\n"),
+					"/app/new_test.go":  []byte("package app\n\nimport \"testing\"\n\nfunc TestNew(t *testing.T) {}\n"),
+					"build/new_test.go": []byte("package build\n\nimport \"testing\"\n\nfunc TestIgnored(t *testing.T) {}\n"),
+				},
+			},
+			want: []string{"TestEdited", "TestNested", "TestNew"},
+		},
+		{
+			name:    "missing directory",
+			opts:    pkg.Options{Patterns: []string{"missing"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := tt.opts
+			opts.FS = fsys
+
+			got, err := pkg.ListWithOptions(context.Background(), opts)
+			if tt.wantErr {
+				var walkErr *pkg.WalkError
+				require.ErrorAs(t, err, &walkErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var names []string
+			for _, test := range got.Tests {
+				names = append(names, test.Name)
+			}
+
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func Test_ListWithOptionsFSDetails(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"go.mod":          {Data: []byte("module github.com/acme/svc\n")},
+		"app/app_test.go": {Data: []byte("package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n")},
+	}
+
+	got, err := pkg.ListWithOptions(context.Background(), pkg.Options{FS: fsys, Patterns: []string{"./..."}})
+	require.NoError(t, err)
+	require.Equal(t, []pkg.TestDetail{
+		{
+			Name:         "TestApp",
+			Package:      "github.com/acme/svc/app",
+			Module:       "github.com/acme/svc",
+			ModuleRoot:   string(filepath.Separator),
+			FileName:     "app_test.go",
+			RelativePath: filepath.Join("app", "app_test.go"),
+			AbsolutePath: filepath.Join(string(filepath.Separator), "app", "app_test.go"),
+			Line:         5,
+			Pos:          37,
+			RunPattern:   "^TestApp$",
+			Command:      []string{"go", "test", "-run", "^TestApp$", "github.com/acme/svc/app"},
+		},
+	}, got.Tests)
+}
+
+func Test_ListWithOptionsOverlay(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "app", "app_test.go")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("package app\n\nimport \"testing\"\n\nfunc TestSaved(t *testing.T) {}\n"),
+		os.ModePerm))
+
+	got, err := pkg.ListWithOptions(context.Background(), pkg.Options{
+		Patterns: []string{tmpDir},
+		Overlay: map[string][]byte{
+			path: []byte("package app\n\nimport \"testing\"\n\nfunc TestUnsaved(t *testing.T) {\n\tt.Run(\"sub\", nil)\n}\n"),
+			filepath.Join(tmpDir, "app", "new_test.go"): []byte("package app\n\nimport \"testing\"\n\n" +
+				"func TestNew(t *testing.T) {}\n"),
+		},
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for _, test := range got.Tests {
+		names = append(names, test.Name)
+	}
+
+	require.Equal(t, []string{"TestNew", "TestUnsaved/sub"}, names)
+}
@@ -0,0 +1,46 @@
+package pkg
+
+import "strings"
+
+// Kind is the kind of a test function, it is derived from the prefix of the name of the top level function.
+type Kind string
+
+const (
+	// KindTest is the kind of the `TestXxx` functions and their subtests.
+	KindTest Kind = "test"
+	// KindBenchmark is the kind of the `BenchmarkXxx` functions and their sub-benchmarks.
+	KindBenchmark Kind = "benchmark"
+	// KindFuzz is the kind of the `FuzzXxx` functions.
+	KindFuzz Kind = "fuzz"
+	// KindExample is the kind of the `ExampleXxx` functions.
+	KindExample Kind = "example"
+	// KindOther is the kind of the functions recognized by custom recognizers without a standard prefix.
+	KindOther Kind = "other"
+)
+
+// Kind returns the kind of the test based on the name of its top level function.
+func (t TestDetail) Kind() Kind {
+	name := topLevelName(t.Name)
+
+	switch {
+	case strings.HasPrefix(name, "Test"):
+		return KindTest
+	case strings.HasPrefix(name, "Benchmark"):
+		return KindBenchmark
+	case strings.HasPrefix(name, "Fuzz"):
+		return KindFuzz
+	case strings.HasPrefix(name, "Example"):
+		return KindExample
+	default:
+		return KindOther
+	}
+}
+
+// topLevelName returns the name of the top level test of the test with the given name.
+func topLevelName(name string) string {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i]
+	}
+
+	return name
+}
@@ -0,0 +1,33 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetail_Kind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want pkg.Kind
+	}{
+		{name: "TestSum", want: pkg.KindTest},
+		{name: "Test/Benchmark_case", want: pkg.KindTest},
+		{name: "BenchmarkSum/small", want: pkg.KindBenchmark},
+		{name: "FuzzSum", want: pkg.KindFuzz},
+		{name: "ExampleSum", want: pkg.KindExample},
+		{name: "CheckSum", want: pkg.KindOther},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, pkg.TestDetail{Name: tt.name}.Kind())
+		})
+	}
+}
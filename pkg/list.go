@@ -3,11 +3,10 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -23,136 +22,387 @@ const (
 )
 
 // TestDetail is a struct that contains the details of a single test.
-// It contains the name of the test, the line number, the file name, the relative path and the absolute path.
-// It also contains the token position (token.Pos) of the test in the file.
+// It contains the name of the test, the import path of the package, the path and the root of the module,
+// the line number, the file name, the relative path and the absolute path.
+// It also contains the token position (token.Pos) of the test in the file as if the file was parsed in its own
+// token.FileSet (the 1-based byte offset of the test in the file), the doc comment of the test and
+// the tags and labels parsed from the `gotest-ls:tags` annotations.
+// Depending on its kind, it contains the anchored `go test -run`, `-bench` or `-fuzz` pattern matching only
+// the test and the `go test` command running it. AvgDuration is the average duration of the test in seconds
+// found in the Timings of the options.
 type TestDetail struct {
-	Name         string    `json:"name"`
-	FileName     string    `json:"fileName"`
-	RelativePath string    `json:"relativePath"`
-	AbsolutePath string    `json:"absolutePath"`
-	Line         int       `json:"line"`
-	Pos          token.Pos `json:"pos"`
+	Name         string            `json:"name"`
+	Package      string            `json:"package,omitempty"`
+	Module       string            `json:"module,omitempty"`
+	ModuleRoot   string            `json:"moduleRoot,omitempty"`
+	FileName     string            `json:"fileName"`
+	RelativePath string            `json:"relativePath"`
+	AbsolutePath string            `json:"absolutePath"`
+	Line         int               `json:"line"`
+	Pos          token.Pos         `json:"pos"`
+	Doc          string            `json:"doc,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	RunPattern   string            `json:"runPattern,omitempty"`
+	BenchPattern string            `json:"benchPattern,omitempty"`
+	FuzzPattern  string            `json:"fuzzPattern,omitempty"`
+	Command      []string          `json:"command,omitempty"`
+	AvgDuration  float64           `json:"avgDuration,omitempty"`
 }
 
 // subTestDetail returns the testname and the position of the subtest in the file.
+// node is the ast node which defines the subtest, it is used to find the comments attached to the subtest.
 type subTestDetail struct {
 	name string
 	pos  token.Pos
+	node ast.Node
 }
 
-// List returns all the go test files in the given directories or a given file.
+// List returns all the go test files in the given directories, files or go package patterns.
+// Directories are walked recursively skipping the directories ignored by the go tool and the paths ignored by
+// the `.gitignore` and `.ignore` files, package patterns like `./...` or `github.com/acme/svc/...` are resolved
+// with the same semantics as `go test`.
 // It returns an error if the given directories are invalid.
 // It returns an empty slice if no tests are found.
 // The returned slice is sorted by the test name.
+// It is a shortcut for ListWithOptions with only the Patterns option set.
 func List(fileOrDirs []string) ([]TestDetail, error) {
-	files, err := loadFiles(fileOrDirs)
+	result, err := ListWithOptions(context.Background(), Options{Patterns: fileOrDirs})
 	if err != nil {
 		return nil, err
 	}
 
-	tests, err := listTests(files)
+	return result.Tests, nil
+}
+
+// ListWithOptions returns all the tests found in the patterns and the workspace of the given options.
+// See List and Options for more details.
+// With the Tolerant option, the syntax errors are reported as diagnostics in the result instead of an error.
+// The listing stops and the error of the context is returned when the context is done.
+func ListWithOptions(ctx context.Context, opts Options) (*Result, error) {
+	filter, err := opts.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	fsys, err := newFileSystem(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := loadFiles(ctx, fsys, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := listTests(ctx, fsys, files, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return tests, nil
+	result.Tests = filter.apply(result.Tests)
+
+	return result, nil
+}
+
+// testFile is a go test file along with the details required to report the tests in it.
+type testFile struct {
+	// path is the path of the test file.
+	path string
+	// base is the directory the relative path of the test file is computed from.
+	base string
+	// pkg is the import path of the package the test file belongs to.
+	pkg string
+	// module is the go module the test file belongs to.
+	module module
+	// absPath is the absolute path of the test file, it is set by resolve.
+	absPath string
+	// relPath is the path of the test file relative to base, it is set by resolve.
+	relPath string
+}
+
+// resolve returns the test file with its absolute path in the given file system and its path relative to base.
+// It returns a *PathError if the paths can't be computed.
+func (f testFile) resolve(fsys *fileSystem) (testFile, error) {
+	absPath, err := fsys.abs(f.path)
+	if err != nil {
+		return f, &PathError{Path: f.path, Err: err}
+	}
+
+	absBase, err := fsys.abs(f.base)
+	if err != nil {
+		return f, &PathError{Path: f.path, Base: f.base, Err: err}
+	}
+
+	relPath, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return f, &PathError{Path: f.path, Base: f.base, Err: err}
+	}
+
+	f.absPath, f.relPath = absPath, relPath
+
+	return f, nil
 }
 
-// loadFiles loads all the go files in the given paths.
-func loadFiles(dirs []string) (map[string][]string, error) {
-	testFiles := make(map[string][]string)
+// loadFiles loads all the go test files in the paths, package patterns and the workspace of the options from the
+// given file system. A file found by multiple patterns is only loaded once.
+func loadFiles(ctx context.Context, fsys *fileSystem, opts Options) ([]testFile, error) {
+	var (
+		testFiles []testFile
+		patterns  []string
+		walker    = newWalker(opts, fsys)
+	)
+
+	if opts.Workspace != "" {
+		files, err := loadWorkspaceFiles(ctx, walker, opts.Workspace)
+		if err != nil {
+			return nil, err
+		}
+
+		testFiles = append(testFiles, files...)
+	}
 
-	for _, dir := range dirs {
-		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+	for _, dir := range opts.Patterns {
+		if opts.FS != nil && strings.HasSuffix(dir, "...") {
+			files, err := walker.walkPackages(ctx, dir)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			if !d.IsDir() && filepath.Ext(path) == ".go" && strings.HasSuffix(path, "_test.go") {
-				testFiles[dir] = append(testFiles[dir], path)
-			}
+			testFiles = append(testFiles, files...)
+
+			continue
+		}
+
+		if opts.FS == nil && isPackagePattern(fsys, dir) {
+			patterns = append(patterns, dir)
+
+			continue
+		}
 
-			return nil
-		})
+		files, err := walker.walk(ctx, dir, filepath.Dir(dir), false)
 		if err != nil {
 			return nil, err
 		}
+
+		testFiles = append(testFiles, files...)
+	}
+
+	packageFiles, err := resolvePackages(ctx, fsys, patterns, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return testFiles, nil
+	return resolveFiles(fsys, append(testFiles, packageFiles...), opts)
 }
 
-// listTests lists all the tests in the given go test files.
-func listTests(files map[string][]string) ([]TestDetail, error) { //nolint: gocognit
-	var tests []TestDetail
+// resolveFiles resolves the paths of the given files and returns the files matching the include and exclude
+// patterns, the build tags and the changes of the given options. A file loaded more than once is only returned once.
+func resolveFiles(fsys *fileSystem, files []testFile, opts Options) ([]testFile, error) {
+	var (
+		seen     = make(map[string]bool, len(files))
+		resolved = files[:0]
+		ctxt     = opts.buildContext(fsys)
+	)
+
+	for _, file := range files {
+		if opts.BaseDir != "" {
+			file.base = opts.BaseDir
+		}
 
-	for dir, testFiles := range files {
-		for _, testFile := range testFiles {
-			set := token.NewFileSet()
+		file, err := file.resolve(fsys)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[file.absPath] || !opts.matches(filepath.ToSlash(file.relPath)) {
+			continue
+		}
+
+		if _, changed := opts.Changes[file.absPath]; opts.Changes != nil && !changed {
+			continue
+		}
 
-			parseFile, err := parser.ParseFile(set, testFile, nil, parser.ParseComments)
+		if opts.BuildTags != nil {
+			match, err := ctxt.MatchFile(filepath.Dir(file.path), filepath.Base(file.path))
 			if err != nil {
-				return nil, err
+				return nil, &WalkError{Path: file.path, Err: err}
 			}
 
-			for _, obj := range parseFile.Scope.Objects {
-				if obj.Kind == ast.Fun {
-					if isGolangTest(obj) {
-						isSubTest := false
+			if !match {
+				continue
+			}
+		}
 
-						if fnDecl, ok := obj.Decl.(*ast.FuncDecl); ok {
-							for i, v := range fnDecl.Body.List {
-								switch identifyTestType(v) {
-								case testTypeSubTest:
-									isSubTest = true
+		seen[file.absPath] = true
+		resolved = append(resolved, file)
+	}
 
-									if test := findSubTestName(v); test != nil {
-										tests = append(tests, buildTestDetail(obj, test.name, dir, testFile, set, test.pos))
-									}
+	return resolved, nil
+}
+
+// listTests lists all the tests in the given go test files read from the given file system.
+// The files are parsed concurrently by streamTests, the result does not depend on the order in which the files are
+// parsed. The listing stops and the error of the context is returned when the context is done.
+func listTests(ctx context.Context, fsys *fileSystem, files []testFile, opts Options) (*Result, error) {
+	var (
+		results = make([]Result, len(files))
+		errs    = make([]error, len(files))
+	)
 
-								case testTypeTableTest:
-									isSubTest = true
-									testNameFieldInStruct := findTableTestNameField(v)
-
-									if testNameFieldInStruct != "" {
-										for j := i; j > 0; j-- {
-											if ttDetails := parseTableTestStructsIfAny(fnDecl.Body.List[j], testNameFieldInStruct); ttDetails != nil {
-												for _, ttDetail := range ttDetails {
-													tests = append(tests, buildTestDetail(obj, ttDetail.name, dir, testFile, set, ttDetail.pos))
-												}
-											}
+	err := streamTests(ctx, fsys, files, opts, func(i int, result Result, err error) error {
+		results[i], errs[i] = result, err
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	for i := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		result.Tests = append(result.Tests, results[i].Tests...)
+		result.Diagnostics = append(result.Diagnostics, results[i].Diagnostics...)
+	}
+
+	sortTests(result.Tests)
+	sortDiagnostics(result.Diagnostics)
+
+	return result, nil
+}
+
+// sortTests sorts the tests by name, the tests with the same name are sorted by their path and line.
+func sortTests(tests []TestDetail) {
+	sort.SliceStable(tests, func(i, j int) bool {
+		if tests[i].Name != tests[j].Name {
+			return tests[i].Name < tests[j].Name
+		}
+
+		if tests[i].AbsolutePath != tests[j].AbsolutePath {
+			return tests[i].AbsolutePath < tests[j].AbsolutePath
+		}
+
+		return tests[i].Line < tests[j].Line
+	})
+}
+
+// listFileTests lists all the tests in the given go test file.
+// Generated files are skipped if the SkipGenerated option is set. With the Tolerant option, the tests found in
+// the parseable parts of a file with syntax errors are listed along with the diagnostics. With the Changes
+// option, only the changed tests of a file changed on some lines are listed.
+func listFileTests(
+	set *token.FileSet,
+	fsys *fileSystem,
+	testFile testFile,
+	opts Options,
+) (Result, error) {
+	nodes, diagnostics, err := listFileNodes(set, fsys, testFile, opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if ranges := opts.Changes[testFile.absPath]; len(ranges) > 0 {
+		nodes = changedNodes(set, nodes, ranges)
+	}
+
+	var tests []TestDetail
+
+	for _, node := range nodes {
+		if !node.parent {
+			tests = append(tests, node.detail)
+		}
+	}
+
+	return Result{Tests: tests, Diagnostics: diagnostics}, nil
+}
+
+// testNode is a test found in a go test file along with the ast node of its source: the declaration of a top
+// level test, the `t.Run` call of a subtest or the literal of a table test case.
+type testNode struct {
+	detail TestDetail
+	node   ast.Node
+	// parent is set for the top level tests with subtests, which are only listed through their subtests.
+	parent bool
+}
+
+// listFileNodes returns the tests in the given go test file with their source, including the top level tests
+// with subtests. See listFileTests for more details.
+func listFileNodes( //nolint: gocognit
+	set *token.FileSet,
+	fsys *fileSystem,
+	testFile testFile,
+	opts Options,
+) ([]testNode, []Diagnostic, error) {
+	var nodes []testNode
+
+	parseFile, diagnostics, err := parseTestFile(set, fsys, testFile.path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if parseFile == nil || parseFile.Scope == nil || (opts.SkipGenerated && isGeneratedFile(parseFile)) {
+		return nil, diagnostics, nil
+	}
+
+	comments := ast.NewCommentMap(set, parseFile, parseFile.Comments)
+
+	for _, obj := range parseFile.Scope.Objects {
+		if obj.Kind == ast.Fun {
+			if opts.recognizes(obj) {
+				isSubTest := false
+				testAnnotations := annotations{}
+
+				fnDecl, ok := obj.Decl.(*ast.FuncDecl)
+				if ok && fnDecl.Body != nil {
+					testAnnotations = parseAnnotations(fnDecl.Doc)
+
+					for i, v := range fnDecl.Body.List {
+						switch identifyTestType(v) {
+						case testTypeSubTest:
+							isSubTest = true
+
+							if test := findSubTestName(v); test != nil {
+								detail := buildTestDetail(obj, test.name, testFile, set, test.pos)
+								parseAnnotations(comments[test.node]...).inherit(testAnnotations).apply(&detail)
+								nodes = append(nodes, testNode{detail: detail, node: test.node})
+							}
+
+						case testTypeTableTest:
+							isSubTest = true
+							testNameFieldInStruct := findTableTestNameField(v)
+
+							if testNameFieldInStruct != "" {
+								for j := i; j > 0; j-- {
+									if ttDetails := parseTableTestStructsIfAny(fnDecl.Body.List[j], testNameFieldInStruct); ttDetails != nil {
+										for _, ttDetail := range ttDetails {
+											detail := buildTestDetail(obj, ttDetail.name, testFile, set, ttDetail.pos)
+											parseAnnotations(comments[ttDetail.node]...).inherit(testAnnotations).apply(&detail)
+											nodes = append(nodes, testNode{detail: detail, node: ttDetail.node})
 										}
 									}
-								case testTypeNone:
-									continue
 								}
 							}
-						}
-
-						if !isSubTest {
-							tests = append(tests, buildTestDetail(obj, "", dir, testFile, set, obj.Pos()))
+						case testTypeNone:
+							continue
 						}
 					}
 				}
+
+				if ok {
+					detail := buildTestDetail(obj, "", testFile, set, obj.Pos())
+					testAnnotations.apply(&detail)
+					nodes = append(nodes, testNode{detail: detail, node: fnDecl, parent: isSubTest})
+				}
 			}
 		}
 	}
 
-	// sort the tests by name
-	sort.Slice(tests, func(i, j int) bool {
-		return strings.Compare(tests[i].Name, tests[j].Name) < 0
-	})
-
-	return tests, nil
-}
-
-// isGolangTest checks if the function name starts with golang test standards
-// it checks for `Test`, `Example` or `Benchmark` prefixes in a function name.
-// Other than test functions all the other functions are ignored.
-func isGolangTest(obj *ast.Object) bool {
-	return strings.HasPrefix(obj.Name, "Test") ||
-		strings.HasPrefix(obj.Name, "Example") ||
-		strings.HasPrefix(obj.Name, "Benchmark")
+	return nodes, diagnostics, nil
 }
 
 // identifyTestType identifies the type of the test based on the given ast node.
@@ -203,11 +453,12 @@ func identifyTestType(v ast.Stmt) testType {
 //	}
 func findSubTestName(v ast.Stmt) *subTestDetail {
 	if expr, ok := v.(*ast.ExprStmt); ok {
-		if callExpr, ok := expr.X.(*ast.CallExpr); ok {
+		if callExpr, ok := expr.X.(*ast.CallExpr); ok && len(callExpr.Args) > 0 {
 			if basic, ok := callExpr.Args[0].(*ast.BasicLit); ok {
 				return &subTestDetail{
 					name: basic.Value,
 					pos:  callExpr.Pos(),
+					node: v,
 				}
 			}
 		}
@@ -217,33 +468,26 @@ func findSubTestName(v ast.Stmt) *subTestDetail {
 }
 
 // buildTestDetail returns the TestDetail object with the information received from the given parameters.
+// The paths of the file must be resolved with resolveFiles before calling it.
 func buildTestDetail(
 	obj *ast.Object,
 	name string,
-	dir string,
-	file string,
+	file testFile,
 	set *token.FileSet,
 	pos token.Pos,
 ) TestDetail {
-	fileAbsPath, err := filepath.Abs(file)
-	if err != nil {
-		panic(fmt.Errorf("failed to get absolute path of file %s: %w", file, err))
-	}
-
-	fileName := filepath.Base(file)
-
-	relativePath, err := filepath.Rel(filepath.Dir(dir), file)
-	if err != nil {
-		panic(fmt.Errorf("failed to get relative path of file %s: %w", file, err))
-	}
+	position := set.Position(pos)
 
 	detail := TestDetail{
 		Name:         obj.Name,
-		FileName:     fileName,
-		RelativePath: relativePath,
-		AbsolutePath: fileAbsPath,
-		Line:         set.Position(pos).Line,
-		Pos:          pos,
+		Package:      file.pkg,
+		Module:       file.module.path,
+		ModuleRoot:   file.module.root,
+		FileName:     filepath.Base(file.path),
+		RelativePath: file.relPath,
+		AbsolutePath: file.absPath,
+		Line:         position.Line,
+		Pos:          token.Pos(position.Offset + 1),
 	}
 
 	if name != "" {
@@ -251,6 +495,8 @@ func buildTestDetail(
 			strings.ReplaceAll(strings.ReplaceAll(name, "\"", ""), " ", "_"))
 	}
 
+	detail.setPatterns()
+
 	return detail
 }
 
@@ -276,7 +522,7 @@ func findTableTestNameField(v ast.Stmt) string {
 				if callExpr, ok := exprStmt.X.(*ast.CallExpr); ok {
 					if selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
 						if ident, ok := selectorExpr.X.(*ast.Ident); ok {
-							if ident.Name == "t" && selectorExpr.Sel.Name == "Run" {
+							if ident.Name == "t" && selectorExpr.Sel.Name == "Run" && len(callExpr.Args) > 0 {
 								if sExpr, ok := callExpr.Args[0].(*ast.SelectorExpr); ok {
 									return strings.ReplaceAll(sExpr.Sel.Name, "\"", "")
 								}
@@ -310,6 +556,7 @@ func parseTableTestStructsIfAny(v ast.Stmt, fieldName string) []subTestDetail {
 												subTestDetail{
 													name: value.Value,
 													pos:  key.Pos(),
+													node: compositeLit,
 												})
 										}
 									}
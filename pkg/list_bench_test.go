@@ -0,0 +1,104 @@
+package pkg_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+// generateLargeTree generates a synthetic tree of packages with the given number of test files.
+// Each file contains a plain test, a test with subtests and a table test.
+func generateLargeTree(tb testing.TB, dir string, files int) {
+	tb.Helper()
+
+	const filesPerPackage = 20
+
+	for i := 0; i < files; i++ {
+		pkgDir := filepath.Join(dir, fmt.Sprintf("svc%03d", i/filesPerPackage/10), fmt.Sprintf("pkg%03d", i/filesPerPackage))
+		require.NoError(tb, os.MkdirAll(pkgDir, 0o755))
+
+		err := os.WriteFile(filepath.Join(pkgDir, fmt.Sprintf("file%05d_test.go", i)), []byte(fmt.Sprintf(`package pkg_test
+
+import "testing"
+
+func TestPlain%[1]d(t *testing.T) {
+	t.Parallel()
+}
+
+func TestSubtests%[1]d(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first", func(t *testing.T) {})
+	t.Run("second", func(t *testing.T) {})
+}
+
+func TestTable%[1]d(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "one", want: 1},
+		{name: "two", want: 2},
+		{name: "three", want: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_ = tt.want
+		})
+	}
+}
+`, i)), os.ModePerm)
+		require.NoError(tb, err)
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	for _, files := range []int{100, 1000, 5000} {
+		files := files
+
+		dir := b.TempDir()
+		generateLargeTree(b, dir, files)
+
+		for _, workers := range []int{1, 4, 16} {
+			b.Run(fmt.Sprintf("files=%d/workers=%d", files, workers), func(b *testing.B) {
+				b.ReportAllocs()
+
+				start := time.Now()
+
+				for i := 0; i < b.N; i++ {
+					if _, err := pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{dir}, Workers: workers}); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				b.ReportMetric(float64(files*b.N)/time.Since(start).Seconds(), "files/s")
+			})
+		}
+	}
+}
+
+func Test_ListIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	generateLargeTree(t, dir, 200)
+
+	want, err := pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{dir}, Workers: 1})
+	require.NoError(t, err)
+	require.Len(t, want.Tests, 200*6)
+
+	for i := 0; i < 5; i++ {
+		got, err := pkg.ListWithOptions(context.Background(), pkg.Options{Patterns: []string{dir}, Workers: 16})
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
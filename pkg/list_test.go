@@ -37,6 +37,8 @@ func Test_List(t *testing.T) {
 					AbsolutePath: fmt.Sprintf("%s/sample/sample_test.go", tmpDir),
 					Line:         7,
 					Pos:          49,
+					RunPattern:   "^TestSomething$",
+					Command:      []string{"go", "test", "-run", "^TestSomething$", fmt.Sprintf("%s/sample", tmpDir)},
 				},
 			},
 		},
@@ -51,6 +53,8 @@ func Test_List(t *testing.T) {
 					AbsolutePath: fmt.Sprintf("%s/sample/sample_test.go", tmpDir),
 					Line:         7,
 					Pos:          49,
+					RunPattern:   "^TestSomething$",
+					Command:      []string{"go", "test", "-run", "^TestSomething$", fmt.Sprintf("%s/sample", tmpDir)},
 				},
 			},
 		},
@@ -125,9 +129,9 @@ var (
 	pwd, _    = os.Getwd()
 	parentDir = pwd[:len(pwd)-len("/pkg")]
 	expected  = []pkg.TestDetail{
-		{Name: "Test/5_+_5_=_10", FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 23, Pos: 265},
-		{Name: "Test/5_-_5_=_0", FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 30, Pos: 355},
-		{Name: "Test/mixed_subtest_1", FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 12, Pos: 111},
-		{Name: "Test/mixed_test_2", FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 48, Pos: 635},
+		{Name: "Test/5_+_5_=_10", Package: "github.com/ninadingole/gotest-ls/tests", Module: "github.com/ninadingole/gotest-ls", ModuleRoot: parentDir, FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 23, Pos: 265, RunPattern: `^Test$/^5_\+_5_=_10$`, Command: []string{"go", "test", "-run", `^Test$/^5_\+_5_=_10$`, "github.com/ninadingole/gotest-ls/tests"}},
+		{Name: "Test/5_-_5_=_0", Package: "github.com/ninadingole/gotest-ls/tests", Module: "github.com/ninadingole/gotest-ls", ModuleRoot: parentDir, FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 30, Pos: 355, RunPattern: `^Test$/^5_-_5_=_0$`, Command: []string{"go", "test", "-run", `^Test$/^5_-_5_=_0$`, "github.com/ninadingole/gotest-ls/tests"}},
+		{Name: "Test/mixed_subtest_1", Package: "github.com/ninadingole/gotest-ls/tests", Module: "github.com/ninadingole/gotest-ls", ModuleRoot: parentDir, FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 12, Pos: 111, RunPattern: `^Test$/^mixed_subtest_1$`, Command: []string{"go", "test", "-run", `^Test$/^mixed_subtest_1$`, "github.com/ninadingole/gotest-ls/tests"}},
+		{Name: "Test/mixed_test_2", Package: "github.com/ninadingole/gotest-ls/tests", Module: "github.com/ninadingole/gotest-ls", ModuleRoot: parentDir, FileName: "table_test.go", RelativePath: "table_test.go", AbsolutePath: fmt.Sprintf("%s/tests/table_test.go", parentDir), Line: 48, Pos: 635, RunPattern: `^Test$/^mixed_test_2$`, Command: []string{"go", "test", "-run", `^Test$/^mixed_test_2$`, "github.com/ninadingole/gotest-ls/tests"}},
 	}
 )
@@ -0,0 +1,223 @@
+package pkg
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// runMatch is a `go test -run` or `-skip` pattern compiled with the semantics of the testing package: the
+// pattern is split into alternatives on the `|` and each alternative into the regexps matching each level of
+// the test names on the `/`, both outside of brackets and parentheses.
+type runMatch [][]*regexp.Regexp
+
+// compileRunMatch compiles the given `go test -run` or `-skip` pattern, it returns nil for an empty pattern.
+// It returns a *MatchError if a regexp of the pattern is invalid.
+func compileRunMatch(pattern string) (runMatch, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	alternatives := splitRunPattern(pattern)
+	match := make(runMatch, len(alternatives))
+
+	for i, levels := range alternatives {
+		for _, level := range levels {
+			re, err := regexp.Compile(rewriteLevel(level))
+			if err != nil {
+				return nil, &MatchError{Pattern: pattern, Err: err}
+			}
+
+			match[i] = append(match[i], re)
+		}
+	}
+
+	return match, nil
+}
+
+// matches checks if the test with the given name matches one of the alternatives of the pattern, each level of
+// the name must match the regexp of the same level. The match is partial if the name has fewer levels than the
+// alternative, the subtests of the test may then match the whole pattern.
+func (m runMatch) matches(name string) (ok, partial bool) {
+	levels := strings.Split(name, "/")
+
+	for _, alternative := range m {
+		if ok, partial = matchLevels(alternative, levels); ok {
+			return ok, partial
+		}
+	}
+
+	return false, false
+}
+
+// matchLevels checks if the levels of a test name match the regexps of an alternative.
+func matchLevels(alternative []*regexp.Regexp, levels []string) (ok, partial bool) {
+	for i, level := range levels {
+		if i >= len(alternative) {
+			break
+		}
+
+		if !alternative[i].MatchString(level) {
+			return false, false
+		}
+	}
+
+	return true, len(levels) < len(alternative)
+}
+
+// splitRunPattern splits a `go test -run` pattern into its alternatives and their levels like the testing
+// package, the `|` and `/` in brackets, in parentheses or escaped by a backslash don't split the pattern.
+func splitRunPattern(pattern string) [][]string {
+	var (
+		alternatives [][]string
+		levels       []string
+		brackets     int
+		parentheses  int
+	)
+
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '[':
+			brackets++
+		case ']':
+			// an unmatched `]` is a literal.
+			if brackets--; brackets < 0 {
+				brackets = 0
+			}
+		case '(':
+			if brackets == 0 {
+				parentheses++
+			}
+		case ')':
+			if brackets == 0 {
+				parentheses--
+			}
+		case '\\':
+			i++
+		case '/', '|':
+			if brackets == 0 && parentheses == 0 {
+				levels = append(levels, pattern[:i])
+
+				if pattern[i] == '|' {
+					alternatives = append(alternatives, levels)
+					levels = nil
+				}
+
+				pattern = pattern[i+1:]
+				i = 0
+
+				continue
+			}
+		}
+
+		i++
+	}
+
+	return append(alternatives, append(levels, pattern))
+}
+
+// rewriteLevel rewrites a level of a pattern like the testing package rewrites the names of the subtests: the
+// spaces are replaced by underscores and the non-printable characters are escaped.
+func rewriteLevel(level string) string {
+	var builder strings.Builder
+
+	for _, r := range level {
+		switch {
+		case unicode.IsSpace(r):
+			builder.WriteByte('_')
+		case !strconv.IsPrint(r):
+			quoted := strconv.QuoteRune(r)
+			builder.WriteString(quoted[1 : len(quoted)-1])
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// FilterByRun returns the tests selected by the given `go test -run` and `-skip` patterns, with the semantics
+// of the go tool: a test is selected if each level of its name matches the regexp of the same level of the run
+// pattern, a test with fewer levels than the pattern is selected as its subtests may match. A test is skipped
+// if the skip pattern matches all its levels, or the levels of one of its parents. Empty patterns select all
+// the tests and skip none. It returns a *MatchError if a pattern is invalid.
+func FilterByRun(tests []TestDetail, run, skip string) ([]TestDetail, error) {
+	runs, err := compileRunMatch(run)
+	if err != nil {
+		return nil, err
+	}
+
+	skips, err := compileRunMatch(skip)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByRun(tests, runs, skips), nil
+}
+
+// filterByRun returns the tests selected by the given compiled run and skip patterns, see FilterByRun.
+func filterByRun(tests []TestDetail, runs, skips runMatch) []TestDetail {
+	if runs == nil && skips == nil {
+		return tests
+	}
+
+	var filtered []TestDetail
+
+	for _, test := range tests {
+		if runs != nil {
+			if ok, _ := runs.matches(test.Name); !ok {
+				continue
+			}
+		}
+
+		if skips != nil {
+			if ok, partial := skips.matches(test.Name); ok && !partial {
+				continue
+			}
+		}
+
+		filtered = append(filtered, test)
+	}
+
+	return filtered
+}
+
+// FilterByKind returns the tests of the given kinds, if no kinds are given, all the tests are returned.
+func FilterByKind(tests []TestDetail, kinds []Kind) []TestDetail {
+	if len(kinds) == 0 {
+		return tests
+	}
+
+	var filtered []TestDetail
+
+	for _, test := range tests {
+		for _, kind := range kinds {
+			if test.Kind() == kind {
+				filtered = append(filtered, test)
+
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// FilterByDepth returns the tests nested in at most the given number of levels, the top level tests are at
+// level 1 and their subtests at level 2. If the depth is not greater than 0, all the tests are returned.
+func FilterByDepth(tests []TestDetail, depth int) []TestDetail {
+	if depth <= 0 {
+		return tests
+	}
+
+	var filtered []TestDetail
+
+	for _, test := range tests {
+		if strings.Count(test.Name, "/") < depth {
+			filtered = append(filtered, test)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,97 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+// testDetails returns the tests with the given names.
+func testDetails(names ...string) []pkg.TestDetail {
+	tests := make([]pkg.TestDetail, 0, len(names))
+	for _, name := range names {
+		tests = append(tests, pkg.TestDetail{Name: name})
+	}
+
+	return tests
+}
+
+// testNames returns the names of the given tests.
+func testNames(tests []pkg.TestDetail) []string {
+	var names []string
+	for _, test := range tests {
+		names = append(names, test.Name)
+	}
+
+	return names
+}
+
+func Test_FilterByRun(t *testing.T) {
+	t.Parallel()
+
+	tests := testDetails("TestA", "TestAB/first", "TestAB/second", "TestB/x/y", "TestC/with_space", "BenchmarkA",
+		"ExampleA")
+
+	cases := []struct {
+		name string
+		run  string
+		skip string
+		want []string
+	}{
+		{name: "no patterns", want: testNames(tests)},
+		{name: "unanchored", run: "TestA", want: []string{"TestA", "TestAB/first", "TestAB/second"}},
+		{name: "anchored", run: "^TestA$", want: []string{"TestA"}},
+		{name: "subtest", run: "TestAB/first", want: []string{"TestAB/first"}},
+		{name: "partial match", run: "TestA$/first|TestB", want: []string{"TestA", "TestB/x/y"}},
+		{name: "alternation in parentheses", run: "TestAB/(first|second)", want: []string{"TestAB/first", "TestAB/second"}},
+		{name: "empty level", run: "/first", want: []string{"TestA", "TestAB/first", "BenchmarkA", "ExampleA"}},
+		{name: "escaped slash", run: `TestB\/x`},
+		{name: "rewritten space", run: "TestC/with space", want: []string{"TestC/with_space"}},
+		{name: "skip parent", skip: "TestAB", want: []string{"TestA", "TestB/x/y", "TestC/with_space", "BenchmarkA", "ExampleA"}},
+		{name: "skip subtest", run: "TestAB", skip: "TestAB/first", want: []string{"TestAB/second"}},
+		{name: "partial skip", run: "^TestA$", skip: "TestA/first", want: []string{"TestA"}},
+		{name: "skip nested", run: "TestB", skip: "TestB/x"},
+	}
+	for _, tt := range cases {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pkg.FilterByRun(tests, tt.run, tt.skip)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, testNames(got))
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := pkg.FilterByRun(tests, "TestA/(", "")
+
+		var matchErr *pkg.MatchError
+		require.ErrorAs(t, err, &matchErr)
+		require.Equal(t, "TestA/(", matchErr.Pattern)
+	})
+}
+
+func Test_FilterByKind(t *testing.T) {
+	t.Parallel()
+
+	tests := testDetails("TestA", "BenchmarkA/small", "ExampleA", "FuzzA")
+
+	require.Equal(t, testNames(tests), testNames(pkg.FilterByKind(tests, nil)))
+	require.Equal(t, []string{"BenchmarkA/small", "FuzzA"},
+		testNames(pkg.FilterByKind(tests, []pkg.Kind{pkg.KindBenchmark, pkg.KindFuzz})))
+}
+
+func Test_FilterByDepth(t *testing.T) {
+	t.Parallel()
+
+	tests := testDetails("TestA", "TestB/x", "TestB/x/y")
+
+	require.Equal(t, testNames(tests), testNames(pkg.FilterByDepth(tests, 0)))
+	require.Equal(t, []string{"TestA"}, testNames(pkg.FilterByDepth(tests, 1)))
+	require.Equal(t, []string{"TestA", "TestB/x"}, testNames(pkg.FilterByDepth(tests, 2)))
+}
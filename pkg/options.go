@@ -0,0 +1,203 @@
+package pkg
+
+import (
+	"go/build"
+	"io/fs"
+	"runtime"
+	"sync"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Options contains the configuration used to find and list the tests.
+// The zero value lists all the tests with the default behaviour of List.
+type Options struct {
+	// Patterns contains the directories, go test files and go package patterns to list the tests from.
+	Patterns []string
+	// FS, if not nil, is the file system the tests are listed from instead of the disk. The root of the FS is
+	// treated as the root directory, the Patterns, the Workspace and the BaseDir are paths in the FS and the
+	// absolute paths of the tests are rooted at `/`. Only the `dir/...` package patterns are supported, they are
+	// resolved by walking the directory without `go list`.
+	FS fs.FS
+	// Overlay contains the contents of files which take precedence over the contents on the disk, or in the FS,
+	// keyed by the path of the file. The overlay files which don't exist are listed as if they existed, which
+	// allows listing the tests of unsaved editor buffers.
+	Overlay map[string][]byte
+	// Workspace is the path of a `go.work` file, the tests of all the modules used by the workspace are listed
+	// along with the tests found by the Patterns.
+	Workspace string
+	// BaseDir is the directory the relative path of the tests is computed from. By default it is the parent of
+	// the directory or file in the Patterns, and the root of the module for the package patterns and the
+	// workspace modules.
+	BaseDir string
+	// Recognizers decide which functions are test functions, a function is a test if one of the recognizers
+	// recognizes it. IsGoTest is used if empty, along with PrefixRecognizer("Fuzz") if the Kinds contain
+	// KindFuzz.
+	Recognizers []Recognizer
+	// BuildTags, if not nil, skips the files whose name or build constraints don't match the current GOOS and
+	// GOARCH with the given build tags, like `go test -tags`.
+	BuildTags []string
+	// Tags only lists the tests carrying all the given annotation tags, see FilterByTags.
+	Tags []string
+	// Run and Skip only list the tests selected by the given `go test -run` and `-skip` patterns, see
+	// FilterByRun.
+	Run  string
+	Skip string
+	// Kinds only lists the tests of the given kinds, see FilterByKind.
+	Kinds []Kind
+	// MaxDepth, if greater than 0, only lists the tests nested in at most the given number of levels, see
+	// FilterByDepth.
+	MaxDepth int
+	// Filter, if not nil, is called for each test found and only the tests for which it returns true are listed.
+	Filter func(TestDetail) bool
+	// Include contains the glob patterns (doublestar syntax) of the test files to list, if empty all the files
+	// are listed. The patterns are matched against the relative path of the test file.
+	Include []string
+	// Exclude contains the glob patterns (doublestar syntax) of the test files to skip.
+	// The patterns are matched against the relative path of the test file.
+	Exclude []string
+	// IncludeIgnored disables the `.gitignore` and `.ignore` files honoured while walking the directories.
+	IncludeIgnored bool
+	// SkipGenerated skips the files with the standard `// Code generated ... DO NOT EDIT.` header.
+	SkipGenerated bool
+	// Tolerant reports the syntax errors as diagnostics instead of failing, the tests found in the parseable
+	// parts of the files are still listed.
+	Tolerant bool
+	// Workers is the number of files parsed concurrently, it defaults to GOMAXPROCS.
+	Workers int
+	// CacheDir, if not empty, is the directory where the result of listing each file is cached, so the files
+	// which did not change are not parsed again, see DefaultCacheDir. The cache is not used with custom
+	// Recognizers. The entries which were not used for a week are pruned.
+	CacheDir string
+	// Changes, if not nil, only lists the tests of the changed files whose source contains a changed line, see
+	// GitChanges. All the tests of a file are listed if it changed as a whole or outside of its tests, and all
+	// the subtests of a top level test changed outside of its subtests. The cache is not used with Changes.
+	Changes Changes
+	// Timings, if not nil, contains the durations of the tests attached to the listed tests as AvgDuration,
+	// see Timings.Ingest.
+	Timings Timings
+
+	// fuzz recognizes the fuzz targets with the default recognizer whatever the Kinds, it is set by Verify.
+	fuzz bool
+}
+
+// workers returns the number of workers used to parse the files.
+func (o Options) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// forEach calls fn with each index from 0 to n-1, concurrently by the number of workers of the options, and
+// waits for all the calls to return.
+func (o Options) forEach(n int, fn func(i int)) {
+	var (
+		wg      sync.WaitGroup
+		workers = make(chan struct{}, o.workers())
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// buildContext returns the build context used to match the build constraints of the files read from the given
+// file system.
+func (o Options) buildContext(fsys *fileSystem) build.Context {
+	ctxt := build.Default
+	ctxt.BuildTags = o.BuildTags
+	ctxt.OpenFile = fsys.openFile
+
+	return ctxt
+}
+
+// testFilter selects the tests matching the run and skip patterns, the kinds, the depth, the tags and the
+// filter of some options, the run and skip patterns are compiled once by compile.
+type testFilter struct {
+	opts  Options
+	runs  runMatch
+	skips runMatch
+}
+
+// compile checks if the glob patterns in the options are valid and compiles their run and skip patterns into the
+// filter of the tests. It returns a *PatternError or a *MatchError if a pattern is invalid.
+func (o Options) compile() (*testFilter, error) {
+	for _, pattern := range append(append([]string(nil), o.Include...), o.Exclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, &PatternError{Pattern: pattern, Err: doublestar.ErrBadPattern}
+		}
+	}
+
+	runs, err := compileRunMatch(o.Run)
+	if err != nil {
+		return nil, err
+	}
+
+	skips, err := compileRunMatch(o.Skip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &testFilter{opts: o, runs: runs, skips: skips}, nil
+}
+
+// apply returns the tests selected by the filter, with their average duration found in the timings of the
+// options.
+func (f *testFilter) apply(tests []TestDetail) []TestDetail {
+	for i := range tests {
+		if duration, ok := f.opts.Timings.Duration(tests[i]); ok {
+			tests[i].AvgDuration = duration.Seconds()
+		}
+	}
+
+	tests = filterByRun(tests, f.runs, f.skips)
+	tests = FilterByDepth(FilterByKind(tests, f.opts.Kinds), f.opts.MaxDepth)
+	tests = FilterByTags(tests, f.opts.Tags)
+	if f.opts.Filter == nil {
+		return tests
+	}
+
+	var filtered []TestDetail
+
+	for _, test := range tests {
+		if f.opts.Filter(test) {
+			filtered = append(filtered, test)
+		}
+	}
+
+	return filtered
+}
+
+// matches checks if the file with the given relative path should be listed.
+func (o Options) matches(relativePath string) bool {
+	for _, pattern := range o.Exclude {
+		if ok, _ := doublestar.Match(pattern, relativePath); ok {
+			return false
+		}
+	}
+
+	if len(o.Include) == 0 {
+		return true
+	}
+
+	for _, pattern := range o.Include {
+		if ok, _ := doublestar.Match(pattern, relativePath); ok {
+			return true
+		}
+	}
+
+	return false
+}
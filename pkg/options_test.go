@@ -0,0 +1,118 @@
+package pkg_test
+
+import (
+	"context"
+	"go/ast"
+	"path/filepath"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListWithOptionsConfiguration(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"app/app_test.go":     "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n\nfunc FuzzApp(f *testing.F) {}\n",
+		"app/ignored_test.go": "//go:build integration\n\npackage app\n\nimport \"testing\"\n\nfunc TestIntegration(t *testing.T) {}\n",
+		"app/spec_test.go":    "package app\n\nimport \"testing\"\n\nfunc TestSpec(t *testing.T) {}\n",
+	}
+
+	writeFiles(t, tmpDir, files)
+
+	tests := []struct {
+		name      string
+		opts      pkg.Options
+		want      []string
+		wantPaths []string
+	}{
+		{
+			name: "default options",
+			opts: pkg.Options{},
+			want: []string{"TestApp", "TestIntegration", "TestSpec"},
+		},
+		{
+			name: "custom recognizers",
+			opts: pkg.Options{Recognizers: []pkg.Recognizer{pkg.PrefixRecognizer("Fuzz"), func(fn *ast.FuncDecl) bool {
+				return fn.Name.Name == "TestSpec"
+			}}},
+			want: []string{"FuzzApp", "TestSpec"},
+		},
+		{
+			name: "build tags",
+			opts: pkg.Options{BuildTags: []string{}},
+			want: []string{"TestApp", "TestSpec"},
+		},
+		{
+			name: "build tags matching the constraints",
+			opts: pkg.Options{BuildTags: []string{"integration"}},
+			want: []string{"TestApp", "TestIntegration", "TestSpec"},
+		},
+		{
+			name: "filter",
+			opts: pkg.Options{Filter: func(test pkg.TestDetail) bool { return test.FileName == "app_test.go" }},
+			want: []string{"TestApp"},
+		},
+		{
+			name: "run and skip patterns",
+			opts: pkg.Options{Run: "^Test", Skip: "Integration"},
+			want: []string{"TestApp", "TestSpec"},
+		},
+		{
+			name: "kinds",
+			opts: pkg.Options{Kinds: []pkg.Kind{pkg.KindFuzz}},
+			want: []string{"FuzzApp"},
+		},
+		{
+			name: "max depth",
+			opts: pkg.Options{MaxDepth: 1, Run: "App"},
+			want: []string{"TestApp"},
+		},
+		{
+			name: "base directory",
+			opts: pkg.Options{BaseDir: filepath.Join(tmpDir, "app"), Include: []string{"app_test.go"}},
+			want: []string{"TestApp"},
+			wantPaths: []string{
+				"app_test.go",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := tt.opts
+			opts.Patterns = []string{tmpDir}
+
+			got, err := pkg.ListWithOptions(context.Background(), opts)
+			require.NoError(t, err)
+
+			var names, paths []string
+			for _, test := range got.Tests {
+				names = append(names, test.Name)
+				paths = append(paths, test.RelativePath)
+			}
+
+			require.Equal(t, tt.want, names)
+
+			if tt.wantPaths != nil {
+				require.Equal(t, tt.wantPaths, paths)
+			}
+		})
+	}
+}
+
+func Test_ListWithOptionsCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pkg.ListWithOptions(ctx, pkg.Options{Patterns: []string{"../tests"}})
+	require.ErrorIs(t, err, context.Canceled)
+}
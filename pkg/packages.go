@@ -0,0 +1,209 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goListPackage is the subset of the `go list -json` output used to find the test files of a package.
+type goListPackage struct {
+	Dir          string
+	ImportPath   string
+	Root         string
+	TestGoFiles  []string
+	XTestGoFiles []string
+	Module       *struct {
+		Path string
+		Dir  string
+	}
+}
+
+// isPackagePattern checks if the given argument should be resolved as a go package pattern instead of a path.
+// Arguments containing `...`, the reserved `std`, `cmd` and `all` patterns and import paths which do not exist on
+// the filesystem are treated as package patterns. Everything else, including go files, is treated as a path.
+func isPackagePattern(fsys *fileSystem, arg string) bool {
+	if strings.HasSuffix(arg, ".go") {
+		return false
+	}
+
+	if strings.Contains(arg, "...") {
+		return true
+	}
+
+	if _, err := fsys.stat(arg); err == nil {
+		return false
+	}
+
+	if arg == "std" || arg == "cmd" || arg == "all" {
+		return true
+	}
+
+	return !filepath.IsAbs(arg) && !strings.HasPrefix(arg, ".") && strings.Contains(arg, "/")
+}
+
+// resolvePackages resolves the given package patterns with the same semantics as `go test` using `go list`.
+// The network is never used to resolve the patterns, only the packages available locally are listed.
+// The build tags of the options are passed to `go list`. The test files of the overlay in the directory of a
+// package are part of the package even if they don't exist on the disk.
+func resolvePackages(ctx context.Context, fsys *fileSystem, patterns []string, opts Options) ([]testFile, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	args := []string{"list", "-e", "-find", "-json=Dir,ImportPath,Root,TestGoFiles,XTestGoFiles,Module"}
+	if opts.BuildTags != nil {
+		args = append(args, "-tags="+strings.Join(opts.BuildTags, ","))
+	}
+
+	cmd := exec.CommandContext(ctx, "go", append(args, patterns...)...)
+	cmd.Env = append(os.Environ(), "GOPROXY=off")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, &WalkError{
+			Path: strings.Join(patterns, " "),
+			Err:  fmt.Errorf("go list: %w: %s", err, strings.TrimSpace(stderr.String())),
+		}
+	}
+
+	var files []testFile
+
+	decoder := json.NewDecoder(&stdout)
+
+	for {
+		var pkg goListPackage
+
+		if err := decoder.Decode(&pkg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, &WalkError{
+				Path: strings.Join(patterns, " "),
+				Err:  fmt.Errorf("failed to decode go list output: %w", err),
+			}
+		}
+
+		base := filepath.Dir(pkg.Dir)
+		if pkg.Module != nil && pkg.Module.Dir != "" {
+			base = pkg.Module.Dir
+		} else if pkg.Root != "" {
+			base = filepath.Join(pkg.Root, "src")
+		}
+
+		var mod module
+		if pkg.Module != nil {
+			mod = module{path: pkg.Module.Path, root: pkg.Module.Dir}
+		}
+
+		for _, name := range fsys.withOverlayTestFiles(pkg.Dir, append(pkg.TestGoFiles, pkg.XTestGoFiles...)) {
+			files = append(files, testFile{
+				path:   filepath.Join(pkg.Dir, name),
+				base:   base,
+				pkg:    pkg.ImportPath,
+				module: mod,
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// isIgnoredDir checks if the directory is ignored by the go tool when matching packages.
+// The go tool ignores `vendor` and `testdata` directories and directories starting with `.` or `_`.
+func isIgnoredDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// moduleResolver finds the go module a directory belongs to, the results are cached per directory.
+type moduleResolver struct {
+	fsys    *fileSystem
+	modules map[string]module
+}
+
+// module contains the path and the root directory of a go module.
+type module struct {
+	path string
+	root string
+}
+
+// newModuleResolver returns a new moduleResolver reading the `go.mod` files from the given file system.
+func newModuleResolver(fsys *fileSystem) *moduleResolver {
+	return &moduleResolver{fsys: fsys, modules: make(map[string]module)}
+}
+
+// find returns the module the given directory belongs to by looking for the closest `go.mod` file.
+// It returns an empty module if the directory is not part of a module.
+func (r *moduleResolver) find(dir string) module {
+	dir, err := r.fsys.abs(dir)
+	if err != nil {
+		return module{}
+	}
+
+	if mod, ok := r.modules[dir]; ok {
+		return mod
+	}
+
+	var mod module
+
+	if data, err := r.fsys.readFile(filepath.Join(dir, "go.mod")); err == nil {
+		mod = module{path: modfile.ModulePath(data), root: dir}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		mod = r.find(parent)
+	}
+
+	r.modules[dir] = mod
+
+	return mod
+}
+
+// testFile returns the testFile for the given path with the package and the module it belongs to.
+// The relative path of the file is computed from the given base directory.
+func (r *moduleResolver) testFile(path, base string) testFile {
+	dir := filepath.Dir(path)
+
+	return testFile{
+		path:   path,
+		base:   base,
+		pkg:    r.importPath(dir),
+		module: r.find(dir),
+	}
+}
+
+// importPath returns the import path of the package in the given directory.
+// It returns an empty string if the directory is not part of a module.
+func (r *moduleResolver) importPath(dir string) string {
+	mod := r.find(dir)
+	if mod.path == "" {
+		return ""
+	}
+
+	absDir, err := r.fsys.abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(mod.root, absDir)
+	if err != nil || rel == "." {
+		return mod.path
+	}
+
+	return mod.path + "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,70 @@
+package pkg_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListPackagePatterns(t *testing.T) {
+	t.Parallel()
+
+	got, err := pkg.List([]string{"../tests/...", "github.com/ninadingole/gotest-ls/tests"})
+	require.NoError(t, err)
+
+	var names []string
+
+	for _, test := range got {
+		require.Equal(t, "github.com/ninadingole/gotest-ls/tests", test.Package)
+		require.Equal(t, filepath.Join("tests", test.FileName), test.RelativePath)
+
+		names = append(names, test.Name)
+	}
+
+	require.Equal(t, []string{
+		"BenchmarkSomething",
+		"Example_something",
+		"Test/5_+_5_=_10",
+		"Test/5_-_5_=_0",
+		"Test/mixed_subtest_1",
+		"Test/mixed_test_2",
+		"TestSomething",
+		"Test_subTestPattern/subtest",
+		"Test_subTestPattern/subtest_2",
+	}, names)
+}
+
+func Test_ListSkipsDirectoriesIgnoredByGo(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	for _, dir := range []string{"app", "app/vendor", "app/testdata", "app/_old", "app/.cache"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "app_test.go"), []byte(`package app_test
+
+import "testing"
+
+func TestApp(t *testing.T) {}
+`), os.ModePerm))
+	}
+
+	got, err := pkg.List([]string{filepath.Join(tmpDir, "app")})
+	require.NoError(t, err)
+	require.Equal(t, []pkg.TestDetail{
+		{
+			Name:         "TestApp",
+			FileName:     "app_test.go",
+			RelativePath: "app/app_test.go",
+			AbsolutePath: fmt.Sprintf("%s/app/app_test.go", tmpDir),
+			Line:         5,
+			Pos:          42,
+			RunPattern:   "^TestApp$",
+			Command:      []string{"go", "test", "-run", "^TestApp$", filepath.Join(tmpDir, "app")},
+		},
+	}, got)
+}
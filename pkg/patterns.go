@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Pattern returns the `go test -run` (or `-bench`, `-fuzz`) pattern which only matches the test with the given
+// name. Each level of the name separated by `/` is escaped and anchored, as `go test` matches the levels of a
+// pattern separated by `/` against the levels of the name of a subtest, so `Test/5_+_5` becomes `^Test$/^5_\+_5$`.
+func Pattern(name string) string {
+	levels := strings.Split(name, "/")
+	for i, level := range levels {
+		levels[i] = "^" + regexp.QuoteMeta(level) + "$"
+	}
+
+	return strings.Join(levels, "/")
+}
+
+// CombinePatterns returns the minimal `go test -run` pattern matching all the tests with the given names.
+// A test whose parent is also given is matched by the pattern of its parent. The tests sharing the same
+// subtests are grouped in a single level, and the remaining tests are combined with the `|` alternation, for
+// example `Test/a`, `Test/b` and `TestSum` become `^Test$/^(a|b)$|^TestSum$`.
+// It returns an empty string if no name is given.
+func CombinePatterns(names []string) string {
+	root := &patternNode{}
+
+	for _, name := range names {
+		if name != "" {
+			root.add(strings.Split(name, "/"))
+		}
+	}
+
+	return strings.Join(root.patterns(), "|")
+}
+
+// patternNode is a level of the names combined by CombinePatterns.
+type patternNode struct {
+	// selected is true if the test of this level is given, it matches all its subtests.
+	selected bool
+	children map[string]*patternNode
+}
+
+// add adds the name with the given levels below the node.
+func (n *patternNode) add(levels []string) {
+	if n.children == nil {
+		n.children = make(map[string]*patternNode)
+	}
+
+	child, ok := n.children[levels[0]]
+	if !ok {
+		child = &patternNode{}
+		n.children[levels[0]] = child
+	}
+
+	if len(levels) == 1 {
+		child.selected = true
+		child.children = nil
+
+		return
+	}
+
+	if !child.selected {
+		child.add(levels[1:])
+	}
+}
+
+// patterns returns the alternatives of the pattern matching the selected children of the node.
+// The children with the same alternatives for their own children are grouped in a single level.
+func (n *patternNode) patterns() []string {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var (
+		groups = make(map[string][]string)
+		rests  = make(map[string][]string)
+		keys   []string
+	)
+
+	for _, name := range names {
+		var rest []string
+		if child := n.children[name]; !child.selected {
+			rest = child.patterns()
+		}
+
+		key := strings.Join(rest, "|")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			rests[key] = rest
+		}
+
+		groups[key] = append(groups[key], regexp.QuoteMeta(name))
+	}
+
+	var patterns []string
+
+	for _, key := range keys {
+		level := "^" + groups[key][0] + "$"
+		if len(groups[key]) > 1 {
+			level = "^(" + strings.Join(groups[key], "|") + ")$"
+		}
+
+		if len(rests[key]) == 0 {
+			patterns = append(patterns, level)
+
+			continue
+		}
+
+		for _, rest := range rests[key] {
+			patterns = append(patterns, level+"/"+rest)
+		}
+	}
+
+	return patterns
+}
+
+// setPatterns sets the pattern matching the test, depending on its kind, and the `go test` command running it.
+// The command targets the package of the test, or the directory of its file if it is not part of a module.
+func (t *TestDetail) setPatterns() {
+	target := t.target()
+	pattern := Pattern(t.Name)
+
+	switch t.Kind() {
+	case KindTest, KindExample:
+		t.RunPattern = pattern
+		t.Command = []string{"go", "test", "-run", pattern, target}
+	case KindBenchmark:
+		t.BenchPattern = pattern
+		t.Command = []string{"go", "test", "-run", "^$", "-bench", pattern, target}
+	case KindFuzz:
+		t.FuzzPattern = pattern
+		t.Command = []string{"go", "test", "-run", "^$", "-fuzz", pattern, target}
+	case KindOther:
+	}
+}
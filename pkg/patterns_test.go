@@ -0,0 +1,108 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Pattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "TestSum", want: "^TestSum$"},
+		{name: "Test/5_+_5_=_10", want: `^Test$/^5_\+_5_=_10$`},
+		{name: "Test/a|b/(c)", want: `^Test$/^a\|b$/^\(c\)$`},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := pkg.Pattern(tt.name)
+			require.Equal(t, tt.want, got)
+
+			// every level of the pattern matches exactly the level of the name.
+			levels := strings.Split(tt.name, "/")
+			for i, level := range strings.Split(got, "/") {
+				require.Truef(t, regexp.MustCompile(level).MatchString(levels[i]), "%s matches %s", level, levels[i])
+				require.Falsef(t, regexp.MustCompile(level).MatchString(levels[i]+"x"), "%s matches %sx", level,
+					levels[i])
+			}
+		})
+	}
+}
+
+func Test_CombinePatterns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		names []string
+		want  string
+	}{
+		{name: "empty", names: nil, want: ""},
+		{name: "single", names: []string{"TestSum"}, want: "^TestSum$"},
+		{name: "top level", names: []string{"TestSum", "TestAdd"}, want: "^(TestAdd|TestSum)$"},
+		{name: "subtests", names: []string{"Test/b", "Test/a", "TestSum"}, want: "^Test$/^(a|b)$|^TestSum$"},
+		{name: "parent selected", names: []string{"Test/a", "Test", "Test/b/c"}, want: "^Test$"},
+		{name: "shared subtests", names: []string{"TestA/x", "TestB/x"}, want: "^(TestA|TestB)$/^x$"},
+		{
+			name:  "nested",
+			names: []string{"Test/a/x", "Test/a/y", "Test/b", "Test/5_+_5"},
+			want:  `^Test$/^(5_\+_5|b)$|^Test$/^a$/^(x|y)$`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, pkg.CombinePatterns(tt.names))
+		})
+	}
+}
+
+func Test_ListCommands(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "calc_test.go"), []byte(`package calc
+
+import "testing"
+
+func TestSum(t *testing.T) {}
+
+func BenchmarkSum(b *testing.B) {}
+
+func FuzzSum(f *testing.F) {}
+`), os.ModePerm))
+
+	got, err := pkg.ListWithOptions(context.Background(), pkg.Options{
+		Patterns:    []string{tmpDir},
+		Recognizers: []pkg.Recognizer{pkg.IsGoTest, pkg.PrefixRecognizer("Fuzz")},
+	})
+	require.NoError(t, err)
+
+	commands := make(map[string][]string)
+	for _, test := range got.Tests {
+		commands[test.Name] = test.Command
+	}
+
+	require.Equal(t, map[string][]string{
+		"TestSum":      {"go", "test", "-run", "^TestSum$", tmpDir},
+		"BenchmarkSum": {"go", "test", "-run", "^$", "-bench", "^BenchmarkSum$", tmpDir},
+		"FuzzSum":      {"go", "test", "-run", "^$", "-fuzz", "^FuzzSum$", tmpDir},
+	}, commands)
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// Recognizer checks if a top level function declared in a go test file is a test function.
+type Recognizer func(fn *ast.FuncDecl) bool
+
+// IsGoTest is the default Recognizer, it checks if the function name starts with golang test standards
+// it checks for `Test`, `Example` or `Benchmark` prefixes in a function name.
+// Other than test functions all the other functions are ignored.
+func IsGoTest(fn *ast.FuncDecl) bool {
+	return PrefixRecognizer("Test", "Example", "Benchmark")(fn)
+}
+
+// PrefixRecognizer returns a Recognizer which recognizes the functions with a name starting with one of the
+// given prefixes.
+func PrefixRecognizer(prefixes ...string) Recognizer {
+	return func(fn *ast.FuncDecl) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(fn.Name.Name, prefix) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// recognizes checks if the given object is a function recognized as a test function by one of the recognizers
+// of the options. IsGoTest is used if no recognizers are configured, along with the fuzz targets if they are
+// listed, see listsFuzz.
+func (o Options) recognizes(obj *ast.Object) bool {
+	fn, ok := obj.Decl.(*ast.FuncDecl)
+	if !ok {
+		return false
+	}
+
+	if len(o.Recognizers) == 0 {
+		return IsGoTest(fn) || (o.listsFuzz() && PrefixRecognizer("Fuzz")(fn))
+	}
+
+	for _, recognizer := range o.Recognizers {
+		if recognizer(fn) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listsFuzz checks if the fuzz targets are recognized by the default recognizer, when they are verified or
+// selected by the Kinds of the options.
+func (o Options) listsFuzz() bool {
+	if o.fuzz {
+		return true
+	}
+
+	for _, kind := range o.Kinds {
+		if kind == KindFuzz {
+			return true
+		}
+	}
+
+	return false
+}
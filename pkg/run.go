@@ -0,0 +1,190 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Statuses of the tests run by Run.
+const (
+	StatusPass   = "pass"
+	StatusFail   = "fail"
+	StatusSkip   = "skip"
+	StatusNotRun = "notrun"
+)
+
+// TestRun is a test listed and run by Run, along with its status, its duration in seconds and its output.
+type TestRun struct {
+	TestDetail
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration"`
+	Output   string  `json:"output,omitempty"`
+}
+
+// Run lists the tests with the given options and runs them with `go test -json`, once per package, with the
+// BuildTags of the options. The tests, the examples and the seed corpus of the fuzz targets are selected with
+// `-run` and the benchmarks with `-bench`, using the minimal pattern matching the listed tests. The packages are
+// run concurrently by the Workers of the options.
+//
+// The tests are returned in the order of the listing with the status, the duration and the output reported by
+// `go test`. The tests of a package which does not build fail with the output of the build, and the tests which
+// did not run, for example because `go test` rewrote their name or because the benchmarks only run once the
+// tests of their package pass, have the StatusNotRun status.
+// A failing test is not an error, Run only returns an error if the tests can't be listed or `go test` can't be
+// started.
+func Run(ctx context.Context, opts Options) ([]TestRun, error) {
+	if opts.FS != nil {
+		return nil, ErrFSNotSupported
+	}
+
+	result, err := ListWithOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		runs  = make([]TestRun, len(result.Tests))
+		dirs  []string
+		tests = make(map[string][]int)
+	)
+
+	for i, test := range result.Tests {
+		runs[i] = TestRun{TestDetail: test, Status: StatusNotRun}
+
+		dir := filepath.Dir(test.AbsolutePath)
+		if _, ok := tests[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+
+		tests[dir] = append(tests[dir], i)
+	}
+
+	errs := make([]error, len(dirs))
+
+	opts.forEach(len(dirs), func(i int) {
+		errs[i] = runPackage(ctx, dirs[i], runs, tests[dirs[i]], opts.BuildTags)
+	})
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return runs, nil
+}
+
+// runPackage runs the tests with the given indexes in runs, all in the package of the given directory, and sets
+// their results.
+func runPackage(ctx context.Context, dir string, runs []TestRun, indexes []int, buildTags []string) error {
+	var tests, benchmarks []string
+
+	for _, i := range indexes {
+		switch runs[i].Kind() {
+		case KindBenchmark:
+			benchmarks = append(benchmarks, runs[i].Name)
+		case KindTest, KindExample, KindFuzz, KindOther:
+			tests = append(tests, runs[i].Name)
+		}
+	}
+
+	args := []string{"test", "-json", "-run", "^$"}
+	if len(tests) > 0 {
+		args[3] = CombinePatterns(tests)
+	}
+
+	if len(benchmarks) > 0 {
+		args = append(args, "-bench", CombinePatterns(benchmarks))
+	}
+
+	if buildTags != nil {
+		args = append(args, "-tags="+strings.Join(buildTags, ","))
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "go", append(args, ".")...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPROXY=off")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// the exit error is expected when a test fails or the package does not build.
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !errors.As(err, &exitErr) {
+		return &WalkError{Path: dir, Err: fmt.Errorf("go test: %w", err)}
+	}
+
+	var (
+		results       = make(map[string]*TestRun)
+		outputs       = make(map[string]*strings.Builder)
+		packageOutput strings.Builder
+		packageFailed bool
+	)
+
+	_ = ReadTestEvents(&stdout, func(event TestEvent) error {
+		if event.Test == "" {
+			switch event.Action {
+			case ActionOutput, ActionBuildOutput:
+				packageOutput.WriteString(event.Output)
+			case ActionFail:
+				packageFailed = true
+			}
+
+			return nil
+		}
+
+		result, ok := results[event.Test]
+		if !ok {
+			result = &TestRun{Status: StatusNotRun}
+			results[event.Test] = result
+			outputs[event.Test] = &strings.Builder{}
+		}
+
+		switch event.Action {
+		case ActionRun:
+			// the benchmarks are reported as run but never as passed, they get the status of their package.
+			result.Status = ""
+		case ActionOutput:
+			outputs[event.Test].WriteString(event.Output)
+		case ActionPass, ActionFail, ActionSkip:
+			result.Status, result.Duration = event.Action, event.Elapsed
+		}
+
+		return nil
+	})
+
+	packageOutput.WriteString(stderr.String())
+
+	for _, i := range indexes {
+		if result, ok := results[runs[i].Name]; ok {
+			runs[i].Status, runs[i].Duration = result.Status, result.Duration
+			if result.Status == "" {
+				runs[i].Status = StatusPass
+				if packageFailed {
+					runs[i].Status = StatusFail
+				}
+			}
+
+			runs[i].Output = outputs[runs[i].Name].String()
+
+			continue
+		}
+
+		if packageFailed && len(results) == 0 {
+			runs[i].Status, runs[i].Output = StatusFail, packageOutput.String()
+		}
+	}
+
+	return nil
+}
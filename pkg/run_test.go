@@ -0,0 +1,69 @@
+package pkg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/run\n\ngo 1.19\n",
+		"app/app_test.go": `package app
+
+import "testing"
+
+func TestPass(t *testing.T) { t.Log("hello") }
+
+func TestFail(t *testing.T) { t.Error("boom") }
+
+func TestSkip(t *testing.T) { t.Skip("later") }
+
+func TestTable(t *testing.T) {
+	t.Run("a", func(t *testing.T) {})
+	t.Run("b", func(t *testing.T) { t.Fail() })
+}
+`,
+		// the benchmarks only run if the tests of their package pass.
+		"bench/bench_test.go": "package bench\n\nimport \"testing\"\n\nfunc BenchmarkLoop(b *testing.B) {\n" +
+			"\tfor i := 0; i < b.N; i++ {\n\t}\n}\n",
+		"broken/x_test.go": "package broken\n\nimport \"testing\"\n\nfunc TestX(t *testing.T) { undefined() }\n",
+	}
+
+	writeFiles(t, tmpDir, files)
+
+	got, err := pkg.Run(context.Background(), pkg.Options{Patterns: []string{tmpDir}})
+	require.NoError(t, err)
+
+	statuses := make(map[string]string)
+	for _, run := range got {
+		statuses[run.Name] = run.Status
+
+		switch run.Name {
+		case "TestPass":
+			require.Contains(t, run.Output, "hello")
+		case "TestX":
+			require.Contains(t, run.Output, "undefined")
+		}
+	}
+
+	require.Equal(t, map[string]string{
+		"BenchmarkLoop": pkg.StatusPass,
+		"TestFail":      pkg.StatusFail,
+		"TestPass":      pkg.StatusPass,
+		"TestSkip":      pkg.StatusSkip,
+		"TestTable/a":   pkg.StatusPass,
+		"TestTable/b":   pkg.StatusFail,
+		"TestX":         pkg.StatusFail,
+	}, statuses)
+}
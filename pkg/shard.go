@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidShard is returned by Shard when the number of shards is not positive or the index is out of range.
+var ErrInvalidShard = errors.New("invalid shard")
+
+// ShardPackage contains the top level tests of a package assigned to a shard and the `go test` command
+// running them.
+type ShardPackage struct {
+	Package string   `json:"package"`
+	Tests   []string `json:"tests"`
+	Command []string `json:"command"`
+}
+
+// Shard partitions the top level tests of the given tests into total balanced shards and returns the tests of
+// the shard with the given zero-based index, grouped by package and sorted by the package.
+//
+// Subtests are never split from their top level test as they can't run without its setup, and benchmarks are
+// skipped as `go test -run` doesn't run them. Each top level test weighs its average duration in timings, or the
+// average of the known durations if it has none, and all the tests weigh the same if timings is empty.
+// The heaviest tests are assigned first to the lightest shard, the ties broken by package and name, so the
+// same tests are always assigned to the same shard.
+func Shard(tests []TestDetail, total, index int, timings Timings) ([]ShardPackage, error) {
+	if total < 1 || index < 0 || index >= total {
+		return nil, fmt.Errorf("%w: index %d of %d shards", ErrInvalidShard, index, total)
+	}
+
+	units := shardUnits(tests, timings)
+
+	var (
+		loads  = make([]float64, total)
+		counts = make([]int, total)
+		byPkg  = make(map[string][]string)
+	)
+
+	for _, unit := range units {
+		shard := 0
+
+		for i := 1; i < total; i++ {
+			if loads[i] < loads[shard] || (loads[i] == loads[shard] && counts[i] < counts[shard]) {
+				shard = i
+			}
+		}
+
+		loads[shard] += unit.weight
+		counts[shard]++
+
+		if shard == index {
+			byPkg[unit.pkg] = append(byPkg[unit.pkg], unit.name)
+		}
+	}
+
+	packages := make([]ShardPackage, 0, len(byPkg))
+
+	for pkg, names := range byPkg {
+		sort.Strings(names)
+
+		packages = append(packages, ShardPackage{
+			Package: pkg,
+			Tests:   names,
+			Command: []string{"go", "test", "-run", CombinePatterns(names), pkg},
+		})
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Package < packages[j].Package
+	})
+
+	return packages, nil
+}
+
+// shardUnit is a top level test assigned to a shard as a whole.
+type shardUnit struct {
+	pkg    string
+	name   string
+	weight float64
+}
+
+// shardUnits returns the top level tests of the given tests with their weight, sorted from the heaviest.
+func shardUnits(tests []TestDetail, timings Timings) []shardUnit {
+	var (
+		units []shardUnit
+		seen  = make(map[string]bool)
+		known []bool
+		sum   float64
+		count int
+	)
+
+	for _, test := range tests {
+		if kind := test.Kind(); kind == KindBenchmark || kind == KindOther {
+			continue
+		}
+
+		unit := shardUnit{pkg: test.target(), name: topLevelName(test.Name), weight: 1}
+
+		key := unit.pkg + "\x00" + unit.name
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		duration, ok := timings.duration(unit.pkg, unit.name)
+		if ok {
+			unit.weight = duration.Seconds()
+			sum += unit.weight
+			count++
+		}
+
+		units = append(units, unit)
+		known = append(known, ok)
+	}
+
+	// the tests without timing weigh the average of the known durations.
+	if count > 0 {
+		for i := range units {
+			if !known[i] {
+				units[i].weight = sum / float64(count)
+			}
+		}
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		if units[i].weight != units[j].weight {
+			return units[i].weight > units[j].weight
+		}
+
+		if units[i].pkg != units[j].pkg {
+			return units[i].pkg < units[j].pkg
+		}
+
+		return units[i].name < units[j].name
+	})
+
+	return units
+}
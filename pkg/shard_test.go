@@ -0,0 +1,91 @@
+package pkg_test
+
+import (
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Shard(t *testing.T) {
+	t.Parallel()
+
+	tests := []pkg.TestDetail{
+		{Name: "TestA", Package: "example.com/a"},
+		{Name: "TestB/one", Package: "example.com/a"},
+		{Name: "TestB/two", Package: "example.com/a"},
+		{Name: "BenchmarkA", Package: "example.com/a"},
+		{Name: "TestC", Package: "example.com/b"},
+		{Name: "ExampleD", Package: "example.com/b"},
+		{Name: "TestSlow", Package: "example.com/b"},
+	}
+
+	shards := func(t *testing.T, total int, timings pkg.Timings) [][]pkg.ShardPackage {
+		t.Helper()
+
+		var shards [][]pkg.ShardPackage
+
+		for index := 0; index < total; index++ {
+			shard, err := pkg.Shard(tests, total, index, timings)
+			require.NoError(t, err)
+
+			shards = append(shards, shard)
+		}
+
+		return shards
+	}
+
+	t.Run("by count", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, [][]pkg.ShardPackage{
+			{
+				{Package: "example.com/a", Tests: []string{"TestA"}, Command: []string{"go", "test", "-run", "^TestA$", "example.com/a"}},
+				{Package: "example.com/b", Tests: []string{"ExampleD", "TestSlow"}, Command: []string{"go", "test", "-run", "^(ExampleD|TestSlow)$", "example.com/b"}},
+			},
+			{
+				{Package: "example.com/a", Tests: []string{"TestB"}, Command: []string{"go", "test", "-run", "^TestB$", "example.com/a"}},
+				{Package: "example.com/b", Tests: []string{"TestC"}, Command: []string{"go", "test", "-run", "^TestC$", "example.com/b"}},
+			},
+		}, shards(t, 2, nil))
+	})
+
+	t.Run("by timings", func(t *testing.T) {
+		t.Parallel()
+
+		// TestC and ExampleD weigh the average of the known durations.
+		timings := pkg.Timings{
+			"example.com/a": {"TestA": {AvgDuration: 1}, "TestB": {AvgDuration: 2}},
+			"example.com/b": {"TestSlow": {AvgDuration: 10}},
+		}
+
+		require.Equal(t, [][]pkg.ShardPackage{
+			{
+				{Package: "example.com/a", Tests: []string{"TestA"}, Command: []string{"go", "test", "-run", "^TestA$", "example.com/a"}},
+				{Package: "example.com/b", Tests: []string{"TestSlow"}, Command: []string{"go", "test", "-run", "^TestSlow$", "example.com/b"}},
+			},
+			{
+				{Package: "example.com/a", Tests: []string{"TestB"}, Command: []string{"go", "test", "-run", "^TestB$", "example.com/a"}},
+				{Package: "example.com/b", Tests: []string{"ExampleD", "TestC"}, Command: []string{"go", "test", "-run", "^(ExampleD|TestC)$", "example.com/b"}},
+			},
+		}, shards(t, 2, timings))
+	})
+
+	t.Run("more shards than tests", func(t *testing.T) {
+		t.Parallel()
+
+		got := shards(t, 8, nil)
+		require.Len(t, got, 8)
+		require.Empty(t, got[7])
+	})
+
+	t.Run("invalid shard", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := pkg.Shard(tests, 2, 2, nil)
+		require.ErrorIs(t, err, pkg.ErrInvalidShard)
+
+		_, err = pkg.Shard(tests, 0, 0, nil)
+		require.ErrorIs(t, err, pkg.ErrInvalidShard)
+	})
+}
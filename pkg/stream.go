@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"context"
+	"go/token"
+	"sync"
+)
+
+// ListFunc lists the tests found in the patterns and the workspace of the given options like ListWithOptions, but
+// instead of accumulating all the tests it calls fn with the tests and the diagnostics of each file as soon as
+// the file is parsed. It allows to report the tests progressively on large repositories.
+//
+// The files are yielded in the order in which they are parsed, which is not deterministic, the tests of a file
+// are sorted by name. Files without tests, after the tags and the filter of the options are applied, and without
+// diagnostics are not yielded. fn is never called concurrently.
+//
+// The listing stops and the error is returned when fn returns an error, a file can't be listed or the context
+// is done.
+func ListFunc(ctx context.Context, opts Options, fn func(Result) error) error {
+	filter, err := opts.compile()
+	if err != nil {
+		return err
+	}
+
+	fsys, err := newFileSystem(opts)
+	if err != nil {
+		return err
+	}
+
+	files, err := loadFiles(ctx, fsys, opts)
+	if err != nil {
+		return err
+	}
+
+	return streamTests(ctx, fsys, files, opts, func(_ int, result Result, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if result.Tests = filter.apply(result.Tests); len(result.Tests) == 0 && len(result.Diagnostics) == 0 {
+			return nil
+		}
+
+		sortTests(result.Tests)
+		sortDiagnostics(result.Diagnostics)
+
+		return fn(result)
+	})
+}
+
+// fileResult is the result of listing the tests of the file at the given index.
+type fileResult struct {
+	index  int
+	result Result
+	err    error
+}
+
+// streamTests lists the tests of the given go test files read from the given file system and calls fn with the
+// index of the file and its result as soon as the file is parsed.
+// The files are parsed concurrently by the number of workers configured in the options, all the files share
+// the same token.FileSet. The results are read from and stored in the cache of the options, if any.
+// fn is called from the calling goroutine only.
+// The listing stops and the error is returned when fn returns an error or the context is done.
+func streamTests(
+	ctx context.Context,
+	fsys *fileSystem,
+	files []testFile,
+	opts Options,
+	fn func(int, Result, error) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		set   = token.NewFileSet()
+		cache = newCache(opts)
+		jobs  = make(chan int)
+		out   = make(chan fileResult)
+		wg    sync.WaitGroup
+	)
+
+	if cache != nil {
+		defer cache.trim()
+	}
+
+	for w := 0; w < opts.workers(); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+
+				result, err := listCachedFileTests(set, fsys, cache, files[i], opts)
+
+				select {
+				case out <- fileResult{index: i, result: result, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		for i := range files {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	var err error
+
+	for res := range out {
+		if err != nil {
+			continue
+		}
+
+		if err = fn(res.index, res.result, res.err); err != nil {
+			cancel()
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
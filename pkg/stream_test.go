@@ -0,0 +1,74 @@
+package pkg_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListFunc(t *testing.T) {
+	t.Parallel()
+
+	want, err := pkg.List([]string{"../tests"})
+	require.NoError(t, err)
+
+	var (
+		got   []pkg.TestDetail
+		files = make(map[string]bool)
+	)
+
+	err = pkg.ListFunc(context.Background(), pkg.Options{Patterns: []string{"../tests"}, Workers: 4},
+		func(result pkg.Result) error {
+			require.NotEmpty(t, result.Tests)
+
+			for _, test := range result.Tests {
+				require.Equal(t, result.Tests[0].AbsolutePath, test.AbsolutePath)
+			}
+
+			require.False(t, files[result.Tests[0].AbsolutePath], "file yielded twice")
+			files[result.Tests[0].AbsolutePath] = true
+
+			got = append(got, result.Tests...)
+
+			return nil
+		})
+	require.NoError(t, err)
+
+	sort.SliceStable(got, func(i, j int) bool {
+		if got[i].Name != got[j].Name {
+			return got[i].Name < got[j].Name
+		}
+
+		return got[i].AbsolutePath < got[j].AbsolutePath
+	})
+
+	require.Equal(t, want, got)
+}
+
+func Test_ListFuncStops(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+	calls := 0
+
+	err := pkg.ListFunc(context.Background(), pkg.Options{Patterns: []string{"../tests"}, Workers: 4},
+		func(result pkg.Result) error {
+			calls++
+
+			return errStop
+		})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = pkg.ListFunc(ctx, pkg.Options{Patterns: []string{"../tests"}}, func(result pkg.Result) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
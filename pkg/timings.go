@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// timingWindow is the number of runs the rolling average of the durations is computed over.
+const timingWindow = 10
+
+// invalidImportChars are the characters which are not valid in an import path, with the non graphic and space
+// characters.
+const invalidImportChars = `!"#$%&'()*,:;<=>?[\]^{|}` + "`\uFFFD"
+
+// Timings are the durations of the tests keyed by the import path of their package and their name.
+// The tests which are not part of a module are keyed by the directory of their file.
+type Timings map[string]map[string]Timing
+
+// Timing is the duration of a test.
+type Timing struct {
+	// AvgDuration is the average duration of the last runs of the test in seconds.
+	AvgDuration float64 `json:"avgDuration"`
+	// Runs is the number of runs of the test recorded.
+	Runs int `json:"runs,omitempty"`
+	// Recent are the durations of the last runs of the test in seconds, the most recent last.
+	Recent []float64 `json:"recent,omitempty"`
+}
+
+// ReadTimings reads the timings from the JSON file at the given path.
+// It returns a WalkError if the file can't be read and a ParseError if it is not valid JSON.
+func ReadTimings(path string) (Timings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &WalkError{Path: path, Err: err}
+	}
+
+	timings := make(Timings)
+	if err := json.Unmarshal(data, &timings); err != nil {
+		return nil, &ParseError{File: path, Err: err}
+	}
+
+	return timings, nil
+}
+
+// WriteTimings writes the timings as JSON to the file at the given path. The file is replaced atomically so
+// a listing reading it concurrently never reads a partial file.
+func WriteTimings(path string, timings Timings) error {
+	data, err := json.MarshalIndent(timings, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return &WalkError{Path: path, Err: err}
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(append(data, '\n')); err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		return &WalkError{Path: path, Err: err}
+	}
+
+	return nil
+}
+
+// Duration returns the average duration of the given test, false if it is unknown.
+func (t Timings) Duration(test TestDetail) (time.Duration, bool) {
+	return t.duration(test.target(), test.Name)
+}
+
+// duration returns the average duration of the test with the given package and name, false if it is unknown.
+func (t Timings) duration(pkg, name string) (time.Duration, bool) {
+	timing, ok := t[pkg][name]
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(timing.AvgDuration * float64(time.Second)), true
+}
+
+// Record adds a run of the test with the given package and name which took elapsed to the timings.
+// The average is the average of the last timingWindow runs, so the durations follow the changes of the tests.
+// The timings recorded without their recent durations are weighted as the average of their previous runs.
+func (t Timings) Record(pkg, name string, elapsed time.Duration) {
+	if t[pkg] == nil {
+		t[pkg] = make(map[string]Timing)
+	}
+
+	timing := t[pkg][name]
+	if len(timing.Recent) == 0 {
+		for i := 0; i < timing.Runs && i < timingWindow-1; i++ {
+			timing.Recent = append(timing.Recent, timing.AvgDuration)
+		}
+	}
+
+	timing.Runs++
+	timing.Recent = append(timing.Recent, elapsed.Seconds())
+
+	if len(timing.Recent) > timingWindow {
+		timing.Recent = append([]float64(nil), timing.Recent[len(timing.Recent)-timingWindow:]...)
+	}
+
+	var total float64
+	for _, duration := range timing.Recent {
+		total += duration
+	}
+
+	timing.AvgDuration = total / float64(len(timing.Recent))
+	t[pkg][name] = timing
+}
+
+// Ingest reads the events written by `go test -json` from r and records the duration of the passed and failed
+// tests matching the given tests in the timings. A test matches if it has the same package and name as one of
+// the given tests or one of their parents. The tests which are not part of a module match the local import
+// path `go test` reports for their directory in GOPATH mode, like `_/src/app`, and are keyed by their directory.
+// It returns the number of durations recorded.
+func (t Timings) Ingest(r io.Reader, tests []TestDetail) (int, error) {
+	known := make(map[string]string)
+
+	for _, test := range tests {
+		pkg := test.Package
+		if pkg == "" {
+			pkg = localImportPath(test.target())
+		}
+
+		levels := strings.Split(test.Name, "/")
+		for i := range levels {
+			known[pkg+"\x00"+strings.Join(levels[:i+1], "/")] = test.target()
+		}
+	}
+
+	var recorded int
+
+	err := ReadTestEvents(r, func(event TestEvent) error {
+		if event.Test == "" || (event.Action != ActionPass && event.Action != ActionFail) {
+			return nil
+		}
+
+		if target, ok := known[event.Package+"\x00"+event.Test]; ok {
+			t.Record(target, event.Test, time.Duration(event.Elapsed*float64(time.Second)))
+			recorded++
+		}
+
+		return nil
+	})
+
+	return recorded, err
+}
+
+// target returns the import path of the package of the test, or the directory of its file if it is not part of
+// a module. It is the package `go test` is run with.
+func (t TestDetail) target() string {
+	if t.Package != "" {
+		return t.Package
+	}
+
+	return filepath.Dir(t.AbsolutePath)
+}
+
+// localImportPath returns the import path `go test` gives in GOPATH mode to the package in the given directory
+// outside of GOPATH: `_` followed by the directory, with the characters which are not valid in an import path
+// replaced by `_`.
+func localImportPath(dir string) string {
+	return path.Join("_", strings.Map(func(r rune) rune {
+		if !unicode.IsGraphic(r) || unicode.IsSpace(r) || strings.ContainsRune(invalidImportChars, r) {
+			return '_'
+		}
+
+		return r
+	}, filepath.ToSlash(dir)))
+}
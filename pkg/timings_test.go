@@ -0,0 +1,145 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadTimings(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "timings.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"example.com/a":{"TestA":{"avgDuration":1.5}}}`), os.ModePerm))
+
+	timings, err := pkg.ReadTimings(path)
+	require.NoError(t, err)
+
+	got, ok := timings.Duration(pkg.TestDetail{Name: "TestA", Package: "example.com/a"})
+	require.True(t, ok)
+	require.Equal(t, 1500*time.Millisecond, got)
+
+	_, ok = timings.Duration(pkg.TestDetail{Name: "TestA/sub", Package: "example.com/a"})
+	require.False(t, ok)
+
+	var walkErr *pkg.WalkError
+	_, err = pkg.ReadTimings(filepath.Join(tmpDir, "missing.json"))
+	require.ErrorAs(t, err, &walkErr)
+
+	var parseErr *pkg.ParseError
+	require.NoError(t, os.WriteFile(path, []byte(`{`), os.ModePerm))
+	_, err = pkg.ReadTimings(path)
+	require.ErrorAs(t, err, &parseErr)
+}
+
+func TestTimings_Record(t *testing.T) {
+	t.Parallel()
+
+	timings := pkg.Timings{}
+	timings.Record("example.com/a", "TestA", 2*time.Second)
+	timings.Record("example.com/a", "TestA", 4*time.Second)
+	require.Equal(t, pkg.Timing{AvgDuration: 3, Runs: 2, Recent: []float64{2, 4}}, timings["example.com/a"]["TestA"])
+
+	// the average only follows the last 10 runs.
+	for i := 0; i < 9; i++ {
+		timings.Record("example.com/a", "TestA", time.Second)
+	}
+
+	require.InDelta(t, 1.3, timings["example.com/a"]["TestA"].AvgDuration, 0.001)
+
+	for i := 0; i < 100; i++ {
+		timings.Record("example.com/a", "TestA", time.Second)
+	}
+
+	require.Equal(t, 1.0, timings["example.com/a"]["TestA"].AvgDuration)
+	require.Len(t, timings["example.com/a"]["TestA"].Recent, 10)
+	require.Equal(t, 111, timings["example.com/a"]["TestA"].Runs)
+
+	// the timings without recent durations weigh as many runs of their average.
+	timings = pkg.Timings{"example.com/a": {"TestA": {AvgDuration: 2, Runs: 50}}}
+	timings.Record("example.com/a", "TestA", time.Second)
+	require.InDelta(t, 1.9, timings["example.com/a"]["TestA"].AvgDuration, 0.001)
+	require.Len(t, timings["example.com/a"]["TestA"].Recent, 10)
+}
+
+func TestTimings_Ingest(t *testing.T) {
+	t.Parallel()
+
+	tests := []pkg.TestDetail{
+		{Name: "TestA", Package: "example.com/a"},
+		{Name: "TestB/sub", Package: "example.com/a"},
+	}
+
+	events := `{"Action":"run","Package":"example.com/a","Test":"TestA"}
+{"Action":"pass","Package":"example.com/a","Test":"TestA","Elapsed":1.5}
+# example.com/b
+b_test.go:3:1: syntax error
+{"Action":"pass","Package":"example.com/a","Test":"TestB/sub","Elapsed":0.5}
+{"Action":"fail","Package":"example.com/a","Test":"TestB","Elapsed":0.7}
+{"Action":"skip","Package":"example.com/a","Test":"TestC","Elapsed":0}
+{"Action":"pass","Package":"example.com/a","Test":"TestRemoved","Elapsed":3}
+{"Action":"pass","Package":"example.com/a","Elapsed":2.9}`
+
+	timings := pkg.Timings{}
+	recorded, err := timings.Ingest(strings.NewReader(events), tests)
+	require.NoError(t, err)
+	require.Equal(t, 3, recorded)
+	require.Equal(t, pkg.Timings{
+		"example.com/a": {
+			"TestA":     {AvgDuration: 1.5, Runs: 1, Recent: []float64{1.5}},
+			"TestB":     {AvgDuration: 0.7, Runs: 1, Recent: []float64{0.7}},
+			"TestB/sub": {AvgDuration: 0.5, Runs: 1, Recent: []float64{0.5}},
+		},
+	}, timings)
+
+	path := filepath.Join(t.TempDir(), "timings.json")
+	require.NoError(t, pkg.WriteTimings(path, timings))
+
+	got, err := pkg.ReadTimings(path)
+	require.NoError(t, err)
+	require.Equal(t, timings, got)
+}
+
+func TestTimings_IngestOutsideModule(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(string(filepath.Separator), "src", "my app")
+	tests := []pkg.TestDetail{{Name: "TestA", AbsolutePath: filepath.Join(dir, "app_test.go")}}
+
+	// go test runs the packages outside of a module in GOPATH mode, with a local import path.
+	events := `{"Action":"pass","Package":"_/src/my_app","Test":"TestA","Elapsed":1.5}`
+
+	timings := pkg.Timings{}
+	recorded, err := timings.Ingest(strings.NewReader(events), tests)
+	require.NoError(t, err)
+	require.Equal(t, 1, recorded)
+
+	duration, ok := timings.Duration(tests[0])
+	require.True(t, ok)
+	require.Equal(t, 1500*time.Millisecond, duration)
+}
+
+func Test_ListWithOptionsTimings(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app_test.go"),
+		[]byte("package app\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n\nfunc TestB(t *testing.T) {}\n"),
+		os.ModePerm))
+
+	got, err := pkg.ListWithOptions(context.Background(), pkg.Options{
+		Patterns: []string{tmpDir},
+		Timings:  pkg.Timings{tmpDir: {"TestA": {AvgDuration: 0.25, Runs: 4}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, got.Tests, 2)
+	require.Equal(t, 0.25, got.Tests[0].AvgDuration)
+	require.Zero(t, got.Tests[1].AvgDuration)
+}
@@ -0,0 +1,218 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrFSNotSupported is returned by Verify when the options list the tests from an FS, `go test` can only run
+// the packages on the disk.
+var ErrFSNotSupported = errors.New("not supported with an FS")
+
+// Verification is the comparison of the top level tests of a package found by the listing with the tests
+// listed by `go test -list`, reported by Verify.
+type Verification struct {
+	// Package is the import path of the package, or its directory if it is not part of a module.
+	Package string `json:"package"`
+	// Dir is the directory of the package.
+	Dir string `json:"dir"`
+	// Missing contains the tests listed by `go test -list` which were not found.
+	Missing []string `json:"missing"`
+	// Extra contains the tests which were found but are not listed by `go test -list`.
+	Extra []string `json:"extra"`
+	// Error is the output of `go test -list` if it failed, for example if the package does not build.
+	Error string `json:"error,omitempty"`
+}
+
+// OK checks if the tests found match the tests listed by `go test -list`.
+func (v Verification) OK() bool {
+	return len(v.Missing) == 0 && len(v.Extra) == 0 && v.Error == ""
+}
+
+// testListName matches the names of the tests written by `go test -list`.
+var testListName = regexp.MustCompile(`^[\pL_][\pL\pN_]*$`)
+
+// Verify lists the tests with the given options and compares, for each package, the top level tests found with
+// the tests listed by running `go test -list '.*'` in the directory of the package, with the BuildTags of the
+// options. The packages are verified concurrently by the Workers of the options and sorted by package.
+//
+// The packages are the packages of all the test files matching the patterns, the tests of the files skipped by
+// the Include, Exclude and SkipGenerated options are reported as missing. The Run, Skip and Kinds options and
+// the Recognizers select the tests listed by `go test` as well, while the Tags, Filter, MaxDepth and Changes
+// options are ignored as `go test` can't select the tests with them. The fuzz targets are verified with the
+// default recognizer, as `go test` lists them.
+//
+// The examples without an output comment are not run, so not listed, by `go test`, they are never reported as
+// extra. As `go test` reads the files on the disk, the tests of the Overlay may be reported as extra.
+// It returns ErrFSNotSupported if the FS of the options is set.
+func Verify(ctx context.Context, opts Options) ([]Verification, error) {
+	if opts.FS != nil {
+		return nil, ErrFSNotSupported
+	}
+
+	opts.Tags, opts.Filter, opts.MaxDepth, opts.Changes = nil, nil, 0, nil
+	opts.fuzz = true
+
+	filter, err := opts.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	verifications, err := verifiedPackages(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ListWithOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]map[string]bool, len(verifications))
+
+	for _, test := range result.Tests {
+		dir := filepath.Dir(test.AbsolutePath)
+		if found[dir] == nil {
+			found[dir] = make(map[string]bool)
+		}
+
+		found[dir][topLevelName(test.Name)] = true
+	}
+
+	opts.forEach(len(verifications), func(i int) {
+		v := &verifications[i]
+
+		listed, err := goTestList(ctx, v.Dir, opts.BuildTags)
+		if err != nil {
+			v.Error = err.Error()
+
+			return
+		}
+
+		v.Missing, v.Extra = []string{}, []string{}
+
+		for _, test := range selectListed(listed, opts, filter) {
+			if !found[v.Dir][test.Name] {
+				v.Missing = append(v.Missing, test.Name)
+			}
+		}
+
+		for name := range found[v.Dir] {
+			kind := TestDetail{Name: name}.Kind()
+			if !listed[name] && kind != KindExample {
+				v.Extra = append(v.Extra, name)
+			}
+		}
+
+		sort.Strings(v.Missing)
+		sort.Strings(v.Extra)
+	})
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	sort.SliceStable(verifications, func(i, j int) bool {
+		if verifications[i].Package != verifications[j].Package {
+			return verifications[i].Package < verifications[j].Package
+		}
+
+		return verifications[i].Dir < verifications[j].Dir
+	})
+
+	return verifications, nil
+}
+
+// verifiedPackages returns the verifications of the packages of the test files matching the patterns of the
+// given options, without the test files selection of the Include, Exclude and SkipGenerated options.
+func verifiedPackages(ctx context.Context, opts Options) ([]Verification, error) {
+	opts.Include, opts.Exclude, opts.SkipGenerated = nil, nil, false
+
+	fsys, err := newFileSystem(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := loadFiles(ctx, fsys, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		verifications []Verification
+		seen          = make(map[string]bool)
+	)
+
+	for _, file := range files {
+		dir := filepath.Dir(file.absPath)
+		if seen[dir] {
+			continue
+		}
+
+		seen[dir] = true
+		verifications = append(verifications, Verification{
+			Package: TestDetail{Package: file.pkg, AbsolutePath: file.absPath}.target(),
+			Dir:     dir,
+		})
+	}
+
+	return verifications, nil
+}
+
+// selectListed returns the tests listed by `go test` which are recognized by the recognizers of the options and
+// selected by the run and skip patterns and the kinds of the filter. The recognizers only see the name of the
+// functions, with an empty signature.
+func selectListed(listed map[string]bool, opts Options, filter *testFilter) []TestDetail {
+	var tests []TestDetail
+
+	for name := range listed {
+		fn := &ast.FuncDecl{Name: ast.NewIdent(name), Type: &ast.FuncType{Params: &ast.FieldList{}}}
+		if opts.recognizes(&ast.Object{Kind: ast.Fun, Name: name, Decl: fn}) {
+			tests = append(tests, TestDetail{Name: name})
+		}
+	}
+
+	return filterByRun(FilterByKind(tests, opts.Kinds), filter.runs, filter.skips)
+}
+
+// goTestList returns the names of the tests listed by `go test -list '.*'` run in the given directory.
+func goTestList(ctx context.Context, dir string, buildTags []string) (map[string]bool, error) {
+	var output bytes.Buffer
+
+	// vet is disabled as it fails on the malformed test names the verification is meant to report.
+	args := []string{"test", "-list", ".*", "-vet=off"}
+	if buildTags != nil {
+		args = append(args, "-tags="+strings.Join(buildTags, ","))
+	}
+
+	cmd := exec.CommandContext(ctx, "go", append(args, ".")...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPROXY=off")
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go test -list: %w: %s", err, strings.TrimSpace(output.String()))
+	}
+
+	names := make(map[string]bool)
+
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		if line := scanner.Text(); testListName.MatchString(line) {
+			names[line] = true
+		}
+	}
+
+	return names, scanner.Err()
+}
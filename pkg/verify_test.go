@@ -0,0 +1,80 @@
+package pkg_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Verify(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/verify\n\ngo 1.19\n",
+		"app/a_test.go": "package app\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n\n" +
+			"func Testify() {}\n\nfunc ExampleA() {}\n",
+		"app/b_test.go": "package app\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) {}\n",
+		"ok/ok_test.go": "package ok\n\nimport \"testing\"\n\nfunc TestOK(t *testing.T) {}\n\n" +
+			"func FuzzOK(f *testing.F) {}\n",
+		"gen/gen_test.go": "package gen\n\nimport \"testing\"\n\nfunc TestGen(t *testing.T) {}\n\n" +
+			"func FuzzGen(f *testing.F) {}\n",
+		"broken/x_test.go": "package broken\n\nimport \"testing\"\n\nfunc TestX(t *testing.T) { undefined() }\n",
+	}
+
+	writeFiles(t, tmpDir, files)
+
+	got, err := pkg.Verify(context.Background(), pkg.Options{
+		Patterns: []string{tmpDir},
+		Exclude:  []string{"**/b_test.go", "**/gen_test.go"},
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	require.Equal(t, "example.com/verify/app", got[0].Package)
+	require.Equal(t, []string{"TestB"}, got[0].Missing)
+	require.Equal(t, []string{"Testify"}, got[0].Extra)
+	require.False(t, got[0].OK())
+
+	require.Equal(t, "example.com/verify/broken", got[1].Package)
+	require.Contains(t, got[1].Error, "undefined")
+	require.False(t, got[1].OK())
+
+	require.Equal(t, "example.com/verify/gen", got[2].Package)
+	require.Equal(t, []string{"FuzzGen", "TestGen"}, got[2].Missing)
+	require.Empty(t, got[2].Extra)
+
+	require.Equal(t, pkg.Verification{
+		Package: "example.com/verify/ok",
+		Dir:     filepath.Join(tmpDir, "ok"),
+		Missing: []string{},
+		Extra:   []string{},
+	}, got[3])
+	require.True(t, got[3].OK())
+
+	got, err = pkg.Verify(context.Background(), pkg.Options{
+		Patterns: []string{filepath.Join(tmpDir, "app")},
+		Exclude:  []string{"**/b_test.go"},
+		Run:      "^TestA$",
+		Tags:     []string{"slow"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []pkg.Verification{{
+		Package: "example.com/verify/app",
+		Dir:     filepath.Join(tmpDir, "app"),
+		Missing: []string{},
+		Extra:   []string{},
+	}}, got)
+
+	_, err = pkg.Verify(context.Background(), pkg.Options{FS: fstest.MapFS{}, Patterns: []string{"./..."}})
+	require.ErrorIs(t, err, pkg.ErrFSNotSupported)
+}
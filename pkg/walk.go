@@ -0,0 +1,276 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"go/ast"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ignoreFiles are the files containing the gitignore style patterns honoured while walking the directories.
+var ignoreFiles = []string{".gitignore", ".ignore"}
+
+// generatedCodeHeader matches the standard comment of the generated go files.
+// See https://golang.org/s/generatedcode for more details.
+var generatedCodeHeader = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// walker walks the directories to find the go test files.
+type walker struct {
+	opts    Options
+	fsys    *fileSystem
+	modules *moduleResolver
+	// ignoreRules contains the rules of the ignore files, per directory, found during the walk.
+	ignoreRules map[string][]ignoreRule
+}
+
+// newWalker returns a new walker reading from the given file system, configured with the given options.
+func newWalker(opts Options, fsys *fileSystem) *walker {
+	return &walker{
+		opts:        opts,
+		fsys:        fsys,
+		modules:     newModuleResolver(fsys),
+		ignoreRules: make(map[string][]ignoreRule),
+	}
+}
+
+// walk returns all the go test files in the given root, the root can be a directory or a go file.
+// The relative path of the files is computed from the given base directory.
+// If skipModules is true, the nested go modules found in the root are skipped.
+// The walk stops and the error of the context is returned when the context is done.
+func (w *walker) walk(ctx context.Context, root, base string, skipModules bool) ([]testFile, error) {
+	var testFiles []testFile
+
+	absRoot, err := w.fsys.abs(root)
+	if err != nil {
+		return nil, &PathError{Path: root, Err: err}
+	}
+
+	if !w.opts.IncludeIgnored {
+		w.loadParentIgnoreRules(absRoot)
+	}
+
+	err = w.fsys.walkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return &WalkError{Path: path, Err: err}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return &PathError{Path: path, Base: root, Err: err}
+		}
+
+		absPath := filepath.Join(absRoot, rel)
+
+		if d.IsDir() {
+			if path != root && (isIgnoredDir(d.Name()) || (skipModules && w.isModuleRoot(path)) || w.ignored(absPath, true)) {
+				return filepath.SkipDir
+			}
+
+			if !w.opts.IncludeIgnored {
+				w.loadIgnoreRules(absPath)
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != ".go" || !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		if path != root && w.ignored(absPath, false) {
+			return nil
+		}
+
+		testFiles = append(testFiles, w.modules.testFile(path, base))
+
+		return nil
+	})
+
+	return testFiles, err
+}
+
+// loadParentIgnoreRules loads the ignore files of the parent directories of the given path up to the root of
+// the git repository. The rules are not loaded if the path is not part of a git repository.
+func (w *walker) loadParentIgnoreRules(path string) {
+	var parents []string
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		parents = append(parents, dir)
+
+		if _, err := w.fsys.stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+
+		if filepath.Dir(dir) == dir {
+			return
+		}
+	}
+
+	for _, dir := range parents {
+		w.loadIgnoreRules(dir)
+	}
+}
+
+// loadIgnoreRules loads the rules of the ignore files in the given directory.
+func (w *walker) loadIgnoreRules(dir string) {
+	if _, ok := w.ignoreRules[dir]; ok {
+		return
+	}
+
+	var rules []ignoreRule
+
+	for _, name := range ignoreFiles {
+		rules = append(rules, readIgnoreFile(w.fsys, filepath.Join(dir, name))...)
+	}
+
+	w.ignoreRules[dir] = rules
+}
+
+// ignored checks if the given absolute path is ignored by the rules of the ignore files in its parent directories.
+// Like git, the rules of the deeper ignore files take precedence and the last matching rule wins.
+func (w *walker) ignored(path string, isDir bool) bool {
+	if w.opts.IncludeIgnored {
+		return false
+	}
+
+	var dirs []string
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	ignored := false
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		rel, err := filepath.Rel(dirs[i], path)
+		if err != nil {
+			continue
+		}
+
+		for _, rule := range w.ignoreRules[dirs[i]] {
+			if rule.matches(filepath.ToSlash(rel), isDir) {
+				ignored = !rule.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+// ignoreRule is a single gitignore style pattern.
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// matches checks if the rule matches the given path relative to the directory of the ignore file.
+func (r ignoreRule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	ok, _ := doublestar.Match(r.pattern, rel)
+
+	return ok
+}
+
+// readIgnoreFile reads the gitignore style rules from the given file.
+// It returns no rules if the file does not exist or can't be read.
+func readIgnoreFile(fsys *fileSystem, path string) []ignoreRule {
+	content, err := fsys.readFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var rules []ignoreRule
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// parseIgnoreRule parses a single line of an ignore file.
+// Patterns without a slash match at any depth, patterns with a slash are relative to the ignore file.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+
+	line = strings.TrimPrefix(line, "\\")
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if strings.Contains(line, "/") {
+		rule.pattern = strings.TrimPrefix(line, "/")
+	} else {
+		rule.pattern = "**/" + line
+	}
+
+	return rule, line != ""
+}
+
+// isGeneratedFile checks if the parsed file has the standard `// Code generated ... DO NOT EDIT.` comment
+// before the package clause.
+func isGeneratedFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+
+		for _, comment := range group.List {
+			if generatedCodeHeader.MatchString(comment.Text) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// walkPackages returns all the go test files of the packages matched by the given `dir/...` pattern, without
+// crossing the boundaries of the nested go modules like `go test`.
+// The relative path of the files is computed from the root of the module the directory belongs to.
+func (w *walker) walkPackages(ctx context.Context, pattern string) ([]testFile, error) {
+	root := filepath.FromSlash(strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"))
+	if root == "" {
+		root = "."
+	}
+
+	base := filepath.Dir(root)
+	if mod := w.modules.find(root); mod.root != "" {
+		base = mod.root
+	}
+
+	return w.walk(ctx, root, base, true)
+}
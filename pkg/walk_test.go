@@ -0,0 +1,98 @@
+package pkg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListWithOptions(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":                   "# build output\nbuild/\n*_gen_test.go\n!keep_gen_test.go\n",
+		"app/.ignore":                  "/local_test.go\n",
+		"app/app_test.go":              "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n",
+		"app/local_test.go":            "package app\n\nimport \"testing\"\n\nfunc TestLocal(t *testing.T) {}\n",
+		"app/nested/local_test.go":     "package nested\n\nimport \"testing\"\n\nfunc TestNested(t *testing.T) {}\n",
+		"app/mocks/mock_test.go":       "package mocks\n\nimport \"testing\"\n\nfunc TestMock(t *testing.T) {}\n",
+		"app/generated_test.go":        "// Code generated by mockgen. DO NOT EDIT.\n\npackage app\n\nimport \"testing\"\n\nfunc TestGenerated(t *testing.T) {}\n",
+		"app/api_gen_test.go":          "package app\n\nimport \"testing\"\n\nfunc TestAPIGen(t *testing.T) {}\n",
+		"app/keep_gen_test.go":         "package app\n\nimport \"testing\"\n\nfunc TestKeepGen(t *testing.T) {}\n",
+		"build/app/app_test.go":        "package app\n\nimport \"testing\"\n\nfunc TestBuild(t *testing.T) {}\n",
+		"node_modules/pkg/pkg_test.go": "package pkg\n\nimport \"testing\"\n\nfunc TestNodeModules(t *testing.T) {}\n",
+	}
+
+	writeFiles(t, tmpDir, files)
+
+	tests := []struct {
+		name    string
+		opts    pkg.Options
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "honour ignore files",
+			opts: pkg.Options{},
+			want: []string{"TestApp", "TestGenerated", "TestKeepGen", "TestMock", "TestNested", "TestNodeModules"},
+		},
+		{
+			name: "include ignored files",
+			opts: pkg.Options{IncludeIgnored: true},
+			want: []string{
+				"TestAPIGen", "TestApp", "TestBuild", "TestGenerated", "TestKeepGen", "TestLocal", "TestMock",
+				"TestNested", "TestNodeModules",
+			},
+		},
+		{
+			name: "skip generated files",
+			opts: pkg.Options{SkipGenerated: true},
+			want: []string{"TestApp", "TestKeepGen", "TestMock", "TestNested", "TestNodeModules"},
+		},
+		{
+			name: "exclude globs",
+			opts: pkg.Options{Exclude: []string{"**/mocks/**", "**/node_modules/**"}},
+			want: []string{"TestApp", "TestGenerated", "TestKeepGen", "TestNested"},
+		},
+		{
+			name: "include globs",
+			opts: pkg.Options{Include: []string{"*/app/{app,keep_gen}_test.go"}},
+			want: []string{"TestApp", "TestKeepGen"},
+		},
+		{
+			name:    "invalid glob",
+			opts:    pkg.Options{Exclude: []string{"[a-"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := tt.opts
+			opts.Patterns = []string{tmpDir}
+
+			got, err := pkg.ListWithOptions(context.Background(), opts)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var names []string
+			for _, test := range got.Tests {
+				names = append(names, test.Name)
+			}
+
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
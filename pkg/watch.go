@@ -0,0 +1,285 @@
+package pkg
+
+import (
+	"context"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	// reloadInterval is the maximum interval between two loads of the test files by Watch, so the test files
+	// added to the directories which are not watched, like a new package in a directory without tests, are found.
+	reloadInterval = time.Minute
+
+	// modTimeGranularity is the margin within which the modification time of a path may not reflect a change made
+	// while the test files were loaded, as the file systems store coarse timestamps.
+	modTimeGranularity = 2 * time.Second
+)
+
+// EventType is the type of change of the test inventory reported by Watch.
+type EventType string
+
+const (
+	// EventAdded is reported when a test is found.
+	EventAdded EventType = "added"
+	// EventRemoved is reported when a test is not found anymore.
+	EventRemoved EventType = "removed"
+	// EventMoved is reported when a test is found in another file of the same package.
+	EventMoved EventType = "moved"
+	// EventChanged is reported when the details of a test, like its line or its tags, change.
+	EventChanged EventType = "changed"
+)
+
+// Event describes a change of the test inventory reported by Watch.
+// Previous contains the details of the test before the change for the moved and changed events.
+type Event struct {
+	Type     EventType   `json:"type"`
+	Test     TestDetail  `json:"test"`
+	Previous *TestDetail `json:"previous,omitempty"`
+}
+
+// Watch lists the tests found in the patterns and the workspace of the given options like ListWithOptions and
+// then polls them every interval, calling fn with the events describing how the tests changed.
+//
+// The first listing reports all the tests as added. Only the test files which were added or whose size or
+// modification time changed are parsed again. While a file can't be parsed, for example while it is being
+// edited, its previous tests are kept.
+//
+// Loading the test files walks the directories and runs `go list` for the package patterns, which is too slow
+// to do on each poll of a large repository. The test files are only loaded again when the directory of a test
+// file, one of its parents in the module or the workspace file changes, and at least every minute to find the
+// test files added to the other directories. Otherwise each poll only stats the known files and directories.
+//
+// A test is identified by its name and the directory of its file. Watch stops and returns the error when fn
+// returns an error, the files can't be loaded or the context is done.
+func Watch(ctx context.Context, opts Options, interval time.Duration, fn func(Event) error) error {
+	filter, err := opts.compile()
+	if err != nil {
+		return err
+	}
+
+	fsys, err := newFileSystem(opts)
+	if err != nil {
+		return err
+	}
+
+	w := &watcher{opts: opts, filter: filter, fsys: fsys, files: make(map[string]watchedFile)}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		previous := w.tests()
+
+		if err := w.scan(ctx); err != nil {
+			return err
+		}
+
+		for _, event := range diffTests(previous, w.tests()) {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// watcher keeps the tests of the files listed by Watch.
+type watcher struct {
+	opts   Options
+	filter *testFilter
+	fsys   *fileSystem
+	files  map[string]watchedFile
+
+	// loaded are the test files found by the last load, started at loadedAt, and modTimes the modification
+	// times of the paths whose changes trigger a new load.
+	loaded   []testFile
+	loadedAt time.Time
+	modTimes map[string]time.Time
+}
+
+// watchedFile is a test file listed by Watch along with its tests.
+type watchedFile struct {
+	size    int64
+	modTime time.Time
+	tests   []TestDetail
+}
+
+// scan loads the test files and lists the tests of the files which were added or changed since the previous scan.
+func (w *watcher) scan(ctx context.Context) error {
+	files, err := w.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		set     = token.NewFileSet()
+		scanned = make(map[string]watchedFile, len(files))
+	)
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		stat, err := w.fsys.stat(file.path)
+		if err != nil {
+			continue
+		}
+
+		previous, ok := w.files[file.absPath]
+		if ok && previous.size == stat.Size() && previous.modTime.Equal(stat.ModTime()) {
+			scanned[file.absPath] = previous
+
+			continue
+		}
+
+		current := watchedFile{size: stat.Size(), modTime: stat.ModTime(), tests: previous.tests}
+
+		if result, err := listFileTests(set, w.fsys, file, w.opts); err == nil {
+			current.tests = w.filter.apply(result.Tests)
+		}
+
+		scanned[file.absPath] = current
+	}
+
+	w.files = scanned
+
+	return nil
+}
+
+// load returns the test files to scan, the files of the previous load are returned unless a watched path
+// changed or they were loaded more than reloadInterval ago.
+func (w *watcher) load(ctx context.Context) ([]testFile, error) {
+	if w.modTimes != nil && time.Since(w.loadedAt) < reloadInterval && !w.changed() {
+		return w.loaded, nil
+	}
+
+	loadedAt := time.Now()
+
+	files, err := loadFiles(ctx, w.fsys, w.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	w.loaded, w.loadedAt, w.modTimes = files, loadedAt, make(map[string]time.Time)
+
+	if w.opts.Workspace != "" {
+		w.modTimes[w.opts.Workspace] = w.modTime(w.opts.Workspace)
+	}
+
+	for _, file := range files {
+		for dir := filepath.Dir(file.absPath); ; dir = filepath.Dir(dir) {
+			if _, ok := w.modTimes[dir]; !ok {
+				w.modTimes[dir] = w.modTime(dir)
+			}
+
+			if file.module.root == "" || !isWithin(file.module.root, dir) {
+				break
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// changed checks if one of the watched paths changed since the last load. A path modified within
+// modTimeGranularity of the load is considered changed, as a change made during the load may not have changed
+// its modification time.
+func (w *watcher) changed() bool {
+	for path, modTime := range w.modTimes {
+		if current := w.modTime(path); !current.Equal(modTime) || current.After(w.loadedAt.Add(-modTimeGranularity)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// modTime returns the modification time of the given path, or the zero time if it can't be read.
+func (w *watcher) modTime(path string) time.Time {
+	stat, err := w.fsys.stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return stat.ModTime()
+}
+
+// tests returns the tests of all the watched files.
+func (w *watcher) tests() []TestDetail {
+	var tests []TestDetail
+
+	for _, file := range w.files {
+		tests = append(tests, file.tests...)
+	}
+
+	sortTests(tests)
+
+	return tests
+}
+
+// diffTests returns the events describing the changes from the previous tests to the current tests, sorted by
+// the name of the test.
+func diffTests(previous, current []TestDetail) []Event {
+	var (
+		events []Event
+		before = make(map[string]TestDetail, len(previous))
+		after  = make(map[string]bool, len(current))
+	)
+
+	for _, test := range previous {
+		if _, ok := before[testIdentity(test)]; !ok {
+			before[testIdentity(test)] = test
+		}
+	}
+
+	for _, test := range current {
+		id := testIdentity(test)
+		if after[id] {
+			continue
+		}
+
+		after[id] = true
+
+		old, ok := before[id]
+
+		switch {
+		case !ok:
+			events = append(events, Event{Type: EventAdded, Test: test})
+		case old.AbsolutePath != test.AbsolutePath:
+			events = append(events, Event{Type: EventMoved, Test: test, Previous: &old})
+		case !reflect.DeepEqual(old, test):
+			events = append(events, Event{Type: EventChanged, Test: test, Previous: &old})
+		}
+	}
+
+	for id, test := range before {
+		if !after[id] {
+			events = append(events, Event{Type: EventRemoved, Test: test})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Test.Name != events[j].Test.Name {
+			return events[i].Test.Name < events[j].Test.Name
+		}
+
+		return events[i].Test.AbsolutePath < events[j].Test.AbsolutePath
+	})
+
+	return events
+}
+
+// testIdentity returns the identity of the test used to match the tests between two scans.
+func testIdentity(test TestDetail) string {
+	return filepath.Dir(test.AbsolutePath) + "\x00" + test.Name
+}
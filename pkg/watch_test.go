@@ -0,0 +1,140 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Watch(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	modTime := time.Now().Add(-time.Hour)
+
+	write := func(t *testing.T, name, content string) {
+		t.Helper()
+
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), os.ModePerm))
+
+		// the modification time is set explicitly as the file may change faster than its resolution.
+		modTime = modTime.Add(time.Second)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	write(t, "a_test.go", "package app\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n\nfunc TestB(t *testing.T) {}\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan pkg.Event)
+	done := make(chan error)
+
+	go func() {
+		done <- pkg.Watch(ctx, pkg.Options{Patterns: []string{tmpDir}}, 10*time.Millisecond, func(event pkg.Event) error {
+			events <- event
+
+			return nil
+		})
+	}()
+
+	next := func(t *testing.T, typ pkg.EventType, name string) pkg.Event {
+		t.Helper()
+
+		select {
+		case event := <-events:
+			require.Equal(t, typ, event.Type)
+			require.Equal(t, name, event.Test.Name)
+
+			return event
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no event received", "expected %s %s", typ, name)
+		}
+
+		return pkg.Event{}
+	}
+
+	// the files are changed while Watch is blocked reporting the last event of a scan, so all the changes are
+	// part of the next scan.
+	next(t, pkg.EventAdded, "TestA")
+	write(t, "b_test.go", "package app\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) {}\n\nfunc TestC(t *testing.T) {}\n")
+	write(t, "a_test.go", "package app\n\nimport \"testing\"\n\n\nfunc TestA(t *testing.T) {}\n")
+	next(t, pkg.EventAdded, "TestB")
+
+	event := next(t, pkg.EventChanged, "TestA")
+	require.Equal(t, 5, event.Previous.Line)
+	require.Equal(t, 6, event.Test.Line)
+
+	event = next(t, pkg.EventMoved, "TestB")
+	require.Equal(t, "a_test.go", event.Previous.FileName)
+	require.Equal(t, "b_test.go", event.Test.FileName)
+
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "a_test.go")))
+	next(t, pkg.EventAdded, "TestC")
+
+	next(t, pkg.EventRemoved, "TestA")
+
+	cancel()
+
+	for {
+		select {
+		case <-events:
+		case err := <-done:
+			require.ErrorIs(t, err, context.Canceled)
+
+			return
+		}
+	}
+}
+
+func Test_WatchNewPackage(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":          "module example.com/watch\n\ngo 1.19\n",
+		"app/app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n",
+	})
+
+	// the directories are not modified within the granularity of the modification times, so the new package is
+	// only found as the module directory changes.
+	modTime := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(tmpDir, "app"), modTime, modTime))
+	require.NoError(t, os.Chtimes(tmpDir, modTime, modTime))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan pkg.Event, 10)
+
+	go func() {
+		_ = pkg.Watch(ctx, pkg.Options{Patterns: []string{tmpDir}}, 10*time.Millisecond, func(event pkg.Event) error {
+			events <- event
+
+			return nil
+		})
+	}()
+
+	added := func(t *testing.T, name string) {
+		t.Helper()
+
+		select {
+		case event := <-events:
+			require.Equal(t, pkg.EventAdded, event.Type)
+			require.Equal(t, name, event.Test.Name)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no event received", "expected %s", name)
+		}
+	}
+
+	added(t, "TestApp")
+	writeFiles(t, tmpDir, map[string]string{
+		"other/other_test.go": "package other\n\nimport \"testing\"\n\nfunc TestOther(t *testing.T) {}\n",
+	})
+	added(t, "TestOther")
+}
@@ -0,0 +1,101 @@
+package pkg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goWorkFile is the name of the file which defines a go workspace.
+const goWorkFile = "go.work"
+
+// FindWorkspace returns the path of the `go.work` file the go tool would use in the given directory.
+// Like the go tool it honours the GOWORK environment variable, `GOWORK=off` disables the workspace.
+// It returns an empty string if the directory is not part of a workspace.
+func FindWorkspace(dir string) string {
+	switch env := os.Getenv("GOWORK"); {
+	case env == "off":
+		return ""
+	case env != "":
+		return env
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, goWorkFile)
+		if stat, err := os.Stat(path); err == nil && !stat.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// loadWorkspaceFiles loads all the go test files in the modules used by the given `go.work` file.
+// The relative path of each file is computed from the root of the module it belongs to.
+func loadWorkspaceFiles(ctx context.Context, walker *walker, workFile string) ([]testFile, error) {
+	roots, err := workspaceModules(walker.fsys, workFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var testFiles []testFile
+
+	for _, root := range roots {
+		files, err := walker.walk(ctx, root, root, true)
+		if err != nil {
+			return nil, err
+		}
+
+		testFiles = append(testFiles, files...)
+	}
+
+	return testFiles, nil
+}
+
+// workspaceModules returns the root directories of the modules used by the given `go.work` file.
+func workspaceModules(fsys *fileSystem, workFile string) ([]string, error) {
+	data, err := fsys.readFile(workFile)
+	if err != nil {
+		return nil, &WalkError{Path: workFile, Err: err}
+	}
+
+	work, err := modfile.ParseWork(workFile, data, nil)
+	if err != nil {
+		return nil, newParseError(workFile, err)
+	}
+
+	workDir, err := fsys.abs(filepath.Dir(workFile))
+	if err != nil {
+		return nil, &PathError{Path: workFile, Err: err}
+	}
+
+	roots := make([]string, 0, len(work.Use))
+
+	for _, use := range work.Use {
+		root := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(workDir, root)
+		}
+
+		roots = append(roots, root)
+	}
+
+	return roots, nil
+}
+
+// isModuleRoot checks if the given directory is the root of a go module.
+func (w *walker) isModuleRoot(dir string) bool {
+	return w.fsys.isFile(filepath.Join(dir, "go.mod"))
+}
@@ -0,0 +1,65 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListWorkspace(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.work":                  "go 1.19\n\nuse (\n\t./billing\n\t./payments\n)\n",
+		"billing/go.mod":           "module github.com/acme/billing\n\ngo 1.19\n",
+		"billing/invoice_test.go":  "package billing\n\nimport \"testing\"\n\nfunc TestInvoice(t *testing.T) {}\n",
+		"payments/go.mod":          "module github.com/acme/payments\n\ngo 1.19\n",
+		"payments/api/api_test.go": "package api\n\nimport \"testing\"\n\nfunc TestAPI(t *testing.T) {}\n",
+		"payments/tools/go.mod":    "module github.com/acme/tools\n\ngo 1.19\n",
+		"payments/tools/x_test.go": "package tools\n\nimport \"testing\"\n\nfunc TestTools(t *testing.T) {}\n",
+	}
+
+	writeFiles(t, tmpDir, files)
+
+	workFile := filepath.Join(tmpDir, "go.work")
+	if os.Getenv("GOWORK") == "" {
+		require.Equal(t, workFile, pkg.FindWorkspace(filepath.Join(tmpDir, "payments", "api")))
+	}
+
+	got, err := pkg.ListWithOptions(context.Background(), pkg.Options{Workspace: workFile})
+	require.NoError(t, err)
+	require.Equal(t, []pkg.TestDetail{
+		{
+			Name:         "TestAPI",
+			Package:      "github.com/acme/payments/api",
+			Module:       "github.com/acme/payments",
+			ModuleRoot:   filepath.Join(tmpDir, "payments"),
+			FileName:     "api_test.go",
+			RelativePath: "api/api_test.go",
+			AbsolutePath: filepath.Join(tmpDir, "payments/api/api_test.go"),
+			Line:         5,
+			Pos:          37,
+			RunPattern:   "^TestAPI$",
+			Command:      []string{"go", "test", "-run", "^TestAPI$", "github.com/acme/payments/api"},
+		},
+		{
+			Name:         "TestInvoice",
+			Package:      "github.com/acme/billing",
+			Module:       "github.com/acme/billing",
+			ModuleRoot:   filepath.Join(tmpDir, "billing"),
+			FileName:     "invoice_test.go",
+			RelativePath: "invoice_test.go",
+			AbsolutePath: filepath.Join(tmpDir, "billing/invoice_test.go"),
+			Line:         5,
+			Pos:          41,
+			RunPattern:   "^TestInvoice$",
+			Command:      []string{"go", "test", "-run", "^TestInvoice$", "github.com/acme/billing"},
+		},
+	}, got.Tests)
+}
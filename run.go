@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// runTests runs the tests found with `go test -json` and writes the listing, as JSON, with the status, the
+// duration and the output of each test. It returns errFailed if a test failed.
+func runTests(proc *args, writer io.Writer) error {
+	opts, err := proc.listOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	runs, err := pkg.Run(ctx, opts)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if runs == nil {
+		runs = []pkg.TestRun{}
+	}
+
+	marshal, err := json.Marshal(runs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errUnknown, err)
+	}
+
+	if proc.pretty {
+		err = prettyPrint(marshal, writer)
+	} else {
+		_, err = fmt.Fprintf(writer, "%s\n", marshal)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var failed int
+
+	for _, run := range runs {
+		if run.Status == pkg.StatusFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d tests", errFailed, failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runTests(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	require.NoError(t, Process(&args{command: commandRun, file: "./tests/sample_test.go"}, &buffer))
+	require.Contains(t, buffer.String(), `"name":"TestSomething"`)
+	require.Contains(t, buffer.String(), `"status":"skip"`)
+	require.Contains(t, buffer.String(), `Skipping...`)
+
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":      goModFile,
+		"app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) { t.Error(\"boom\") }\n",
+	})
+
+	buffer.Reset()
+	err := Process(&args{command: commandRun, dirs: []string{tmpDir}}, &buffer)
+	require.ErrorIs(t, err, errFailed)
+	require.Equal(t, exitCodeFailed, exitCode(err))
+	require.Contains(t, buffer.String(), `"status":"fail"`)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// writeShard writes the `go test` commands running the top level tests assigned to the shard requested by the
+// user, one command per package. The tests are weighted by the timing file if one is provided.
+func writeShard(proc *args, writer io.Writer) error {
+	opts, err := proc.listOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	result, err := pkg.ListWithOptions(ctx, opts)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	packages, err := pkg.Shard(result.Tests, proc.total, proc.index, opts.Timings)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errShardArgs, err)
+	}
+
+	for _, p := range packages {
+		if _, err := fmt.Fprintln(writer, shellCommand(p.Command)); err != nil {
+			return fmt.Errorf("%s: %w", errUnknown, err)
+		}
+	}
+
+	return nil
+}
+
+// shellCommand returns the command as a line which can be executed by a POSIX shell, the arguments containing
+// characters other than letters, digits and `-_./=` are single quoted.
+func shellCommand(argv []string) string {
+	quoted := make([]string, len(argv))
+
+	for i, arg := range argv {
+		quoted[i] = arg
+
+		if arg == "" || strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=") != "" {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_shellCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		argv []string
+		want string
+	}{
+		{
+			name: "plain arguments",
+			argv: []string{"go", "test", "-run", "TestSum", "github.com/acme/svc/math"},
+			want: "go test -run TestSum github.com/acme/svc/math",
+		},
+		{
+			name: "pattern",
+			argv: []string{"go", "test", "-run", "^(TestA|TestB)$", "./math"},
+			want: "go test -run '^(TestA|TestB)$' ./math",
+		},
+		{
+			name: "single quote",
+			argv: []string{"go", "test", "-run", "^Test$/^it's$", ""},
+			want: `go test -run '^Test$/^it'\''s$' ''`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, shellCommand(tt.argv))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ninadingole/gotest-ls/pkg"
+)
+
+// verifyTests writes, as JSON, the comparison of the top level tests found in each package with the tests listed
+// by `go test -list`. It returns errVerify if the tests of a package don't match.
+func verifyTests(proc *args, writer io.Writer) error {
+	opts, err := proc.listOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := proc.context()
+	defer cancel()
+
+	verifications, err := pkg.Verify(ctx, opts)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if verifications == nil {
+		verifications = []pkg.Verification{}
+	}
+
+	marshal, err := json.Marshal(verifications)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errUnknown, err)
+	}
+
+	if proc.pretty {
+		err = prettyPrint(marshal, writer)
+	} else {
+		_, err = fmt.Fprintf(writer, "%s\n", marshal)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var mismatches int
+
+	for _, verification := range verifications {
+		if !verification.OK() {
+			mismatches++
+		}
+	}
+
+	if mismatches > 0 {
+		return fmt.Errorf("%w: %d packages", errVerify, mismatches)
+	}
+
+	return nil
+}
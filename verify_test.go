@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_verifyTests(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	require.NoError(t, Process(&args{command: commandVerify, dirs: []string{"./tests"}}, &buffer))
+	require.Contains(t, buffer.String(), `"package":"github.com/ninadingole/gotest-ls/tests"`)
+	require.Contains(t, buffer.String(), `"missing":[],"extra":[]`)
+
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":      goModFile,
+		"app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {}\n\nfunc Testify() {}\n",
+	})
+
+	buffer.Reset()
+	err := Process(&args{command: commandVerify, dirs: []string{tmpDir}}, &buffer)
+	require.ErrorIs(t, err, errVerify)
+	require.Equal(t, exitCodeVerify, exitCode(err))
+	require.Contains(t, buffer.String(), `"missing":[],"extra":["Testify"]`)
+}
+
+func Test_verifyExcludedTests(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":      goModFile,
+		"gen_test.go": "package app\n\nimport \"testing\"\n\nfunc TestGen(t *testing.T) {}\n",
+	})
+
+	var buffer bytes.Buffer
+
+	err := Process(&args{command: commandVerify, dirs: []string{tmpDir}, exclude: []string{"**/gen_test.go"}}, &buffer)
+	require.ErrorIs(t, err, errVerify)
+	require.Contains(t, buffer.String(), `"missing":["TestGen"],"extra":[]`)
+}